@@ -71,13 +71,13 @@ func WaitForDatabase(connStr string) (*sql.DB, error) {
 }
 
 func InitDatabase(db *sql.DB) error {
-	// Tabellen erstellen falls sie nicht existieren
+	// Tabellen erstellen falls sie nicht existieren, Guthaben werden in Cent (1/100 Müller Coin) gespeichert
 	createUsersTable := `
 	CREATE TABLE IF NOT EXISTS users (
 		id SERIAL PRIMARY KEY,
 		user_id TEXT NOT NULL,
 		guild_id TEXT NOT NULL,
-		balance REAL DEFAULT 1000,
+		balance BIGINT DEFAULT 100000,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 		UNIQUE(user_id, guild_id)
@@ -88,10 +88,111 @@ func InitDatabase(db *sql.DB) error {
 		return fmt.Errorf("fehler beim Erstellen der users-Tabelle: %v", err)
 	}
 
+	// Alte REAL-Guthaben in Cent umrechnen, der Umweg über double precision vermeidet
+	// die Rundung auf 6 Stellen bei der direkten Umwandlung von real nach numeric.
+	// Die Ansicht user_stats aus init.sql hängt an der Spalte und wird neu erstellt.
+	migrateUserBalances := `
+	DO $$
+	DECLARE
+		has_stats_view BOOLEAN;
+	BEGIN
+		IF (SELECT data_type FROM information_schema.columns
+			WHERE table_name = 'users' AND column_name = 'balance') = 'real' THEN
+			SELECT EXISTS (SELECT 1 FROM information_schema.views WHERE table_name = 'user_stats') INTO has_stats_view;
+			DROP VIEW IF EXISTS user_stats;
+
+			ALTER TABLE users ALTER COLUMN balance DROP DEFAULT;
+			ALTER TABLE users ALTER COLUMN balance TYPE BIGINT USING ROUND(balance::double precision::numeric * 100);
+			ALTER TABLE users ALTER COLUMN balance SET DEFAULT 100000;
+
+			IF has_stats_view THEN
+				CREATE VIEW user_stats AS
+				SELECT guild_id, COUNT(*) AS total_users, SUM(balance) AS total_balance, AVG(balance) AS avg_balance,
+					MAX(balance) AS max_balance, MIN(balance) AS min_balance
+				FROM users
+				GROUP BY guild_id;
+			END IF;
+		END IF;
+	END $$;`
+
+	_, err = db.Exec(migrateUserBalances)
+	if err != nil {
+		return fmt.Errorf("fehler beim Umstellen der Guthaben auf Cent: %v", err)
+	}
+
+	// Append-only Ledger für jede Bewegung von Müller Coins
+	createTransactionsTable := `
+	CREATE TABLE IF NOT EXISTS transactions (
+		id BIGSERIAL PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL,
+		amount BIGINT NOT NULL,
+		kind TEXT NOT NULL,
+		reference TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	_, err = db.Exec(createTransactionsTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der transactions-Tabelle: %v", err)
+	}
+
+	// Buchungen aus der Zeit vor der Umstellung ebenfalls in Cent umrechnen, Rundungsdifferenzen
+	// zwischen den einzeln gerundeten Buchungen und dem Guthaben werden einmalig ausgeglichen
+	migrateTransactionAmounts := `
+	DO $$
+	BEGIN
+		IF (SELECT data_type FROM information_schema.columns
+			WHERE table_name = 'transactions' AND column_name = 'amount') = 'double precision' THEN
+			ALTER TABLE transactions ALTER COLUMN amount TYPE BIGINT USING ROUND(amount::numeric * 100);
+
+			INSERT INTO transactions (user_id, guild_id, amount, kind, reference)
+			SELECT u.user_id, u.guild_id, u.balance - SUM(t.amount), 'rundung', 'migration'
+			FROM users u JOIN transactions t ON t.user_id = u.user_id AND t.guild_id = u.guild_id
+			GROUP BY u.user_id, u.guild_id, u.balance
+			HAVING u.balance <> SUM(t.amount);
+		END IF;
+	END $$;`
+
+	_, err = db.Exec(migrateTransactionAmounts)
+	if err != nil {
+		return fmt.Errorf("fehler beim Umstellen der Buchungen auf Cent: %v", err)
+	}
+
+	// Konten aus der Zeit vor dem Ledger erhalten einen Übertrag in Höhe ihres Guthabens
+	_, err = db.Exec(`INSERT INTO transactions (user_id, guild_id, amount, kind, reference)
+		SELECT u.user_id, u.guild_id, u.balance, 'eroeffnung', 'migration' FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM transactions t WHERE t.user_id = u.user_id AND t.guild_id = u.guild_id)`)
+	if err != nil {
+		return fmt.Errorf("fehler beim Übertragen der Guthaben ins Ledger: %v", err)
+	}
+
+	// Spielsperren mit Ablaufzeit, damit mehrere Bot-Instanzen nicht parallel für denselben Spieler spielen
+	createPlayerLocksTable := `
+	CREATE TABLE IF NOT EXISTS player_locks (
+		user_id TEXT NOT NULL,
+		guild_id TEXT NOT NULL,
+		owner TEXT NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (user_id, guild_id)
+	);`
+
+	_, err = db.Exec(createPlayerLocksTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der player_locks-Tabelle: %v", err)
+	}
+
+	// Abgelaufene Sperren aus früheren Läufen aufräumen
+	_, err = db.Exec("DELETE FROM player_locks WHERE expires_at < now()")
+	if err != nil {
+		return fmt.Errorf("fehler beim Aufräumen der player_locks-Tabelle: %v", err)
+	}
+
 	// Indizes erstellen
 	createIndexes := []string{
 		"CREATE INDEX IF NOT EXISTS idx_users_user_guild ON users(user_id, guild_id);",
 		"CREATE INDEX IF NOT EXISTS idx_users_balance ON users(balance DESC);",
+		"CREATE INDEX IF NOT EXISTS idx_transactions_user_guild ON transactions(user_id, guild_id, id DESC);",
 	}
 
 	for _, indexSQL := range createIndexes {
@@ -122,5 +223,315 @@ func InitDatabase(db *sql.DB) error {
 		log.Printf("Warnung: Fehler beim Erstellen des Update-Triggers: %v", err)
 	}
 
+	// Vorlesungs-Timer Konfiguration pro Gilde
+	createTimerConfigTable := `
+	CREATE TABLE IF NOT EXISTS timer_config (
+		guild_id TEXT PRIMARY KEY,
+		ical_url TEXT NOT NULL,
+		channel_id TEXT NOT NULL,
+		timezone TEXT NOT NULL DEFAULT 'Europe/Berlin',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	_, err = db.Exec(createTimerConfigTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der timer_config-Tabelle: %v", err)
+	}
+
+	// Bisher fest eingetragene Konfiguration (MGH-TINF23) übernehmen
+	_, err = db.Exec(`INSERT INTO timer_config (guild_id, ical_url, channel_id, timezone)
+		VALUES ($1, $2, $3, $4) ON CONFLICT (guild_id) DO NOTHING`,
+		"1181238521734901770", "https://stuv.app/MGH-TINF23/ical", "1236999352329965608", "Europe/Berlin")
+	if err != nil {
+		log.Printf("Warnung: Fehler beim Übernehmen der Standard-Timer-Konfiguration: %v", err)
+	}
+
+	// Optionaler Kanal für die Fortschrittsanzeigen, bisher fest im Code hinterlegt
+	_, err = db.Exec("ALTER TABLE timer_config ADD COLUMN IF NOT EXISTS progress_channel_id TEXT NOT NULL DEFAULT ''")
+	if err != nil {
+		return fmt.Errorf("fehler beim Erweitern der timer_config-Tabelle: %v", err)
+	}
+
+	_, err = db.Exec("UPDATE timer_config SET progress_channel_id = $1 WHERE guild_id = $2 AND progress_channel_id = ''",
+		"1328643078763843604", "1181238521734901770")
+	if err != nil {
+		log.Printf("Warnung: Fehler beim Übernehmen des Standard-Fortschrittskanals: %v", err)
+	}
+
+	// Filterregeln für Vorlesungen (Dauergrenzen pro Gilde, Regeln als eigene Tabelle)
+	createLectureFilterTable := `
+	ALTER TABLE timer_config ADD COLUMN IF NOT EXISTS min_duration_minutes INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE timer_config ADD COLUMN IF NOT EXISTS max_duration_minutes INTEGER NOT NULL DEFAULT 240;
+
+	CREATE TABLE IF NOT EXISTS lecture_filters (
+		id SERIAL PRIMARY KEY,
+		guild_id TEXT NOT NULL,
+		action TEXT NOT NULL,
+		field TEXT NOT NULL,
+		pattern TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_lecture_filters_guild ON lecture_filters(guild_id);`
+
+	_, err = db.Exec(createLectureFilterTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der lecture_filters-Tabelle: %v", err)
+	}
+
+	// Pause, bis zu der aufeinanderfolgende Vorlesungen einen Block bilden
+	_, err = db.Exec("ALTER TABLE timer_config ADD COLUMN IF NOT EXISTS block_gap_minutes INTEGER NOT NULL DEFAULT 30")
+	if err != nil {
+		return fmt.Errorf("fehler beim Erweitern der timer_config-Tabelle: %v", err)
+	}
+
+	// Müller Coins fürs Einchecken in eine Vorlesung
+	_, err = db.Exec("ALTER TABLE timer_config ADD COLUMN IF NOT EXISTS attendance_reward INTEGER NOT NULL DEFAULT 25")
+	if err != nil {
+		return fmt.Errorf("fehler beim Erweitern der timer_config-Tabelle: %v", err)
+	}
+
+	// Aktives Vorlesungs-Embed pro Gilde, damit es Neustarts übersteht
+	createLectureStateTable := `
+	CREATE TABLE IF NOT EXISTS lecture_state (
+		guild_id TEXT PRIMARY KEY,
+		channel_id TEXT NOT NULL,
+		message_id TEXT NOT NULL,
+		lecture_slot TEXT NOT NULL,
+		lecture_date TEXT NOT NULL,
+		lecture_name TEXT NOT NULL,
+		lecture_start TIMESTAMPTZ NOT NULL,
+		lecture_end TIMESTAMPTZ NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	_, err = db.Exec(createLectureStateTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der lecture_state-Tabelle: %v", err)
+	}
+
+	// Pausen-Embeds nutzen denselben Zustand wie Vorlesungen
+	_, err = db.Exec("ALTER TABLE lecture_state ADD COLUMN IF NOT EXISTS mode TEXT NOT NULL DEFAULT 'lecture'")
+	if err != nil {
+		return fmt.Errorf("fehler beim Erweitern der lecture_state-Tabelle: %v", err)
+	}
+
+	// Parallele Vorlesungen bekommen eigene Embeds, das Haupt-Embed hat einen leeren Schlüssel
+	migrateLectureStateKey := `
+	ALTER TABLE lecture_state ADD COLUMN IF NOT EXISTS lecture_key TEXT NOT NULL DEFAULT '';
+
+	DO $$
+	BEGIN
+		IF NOT EXISTS (
+			SELECT 1 FROM information_schema.key_column_usage
+			WHERE table_name = 'lecture_state' AND constraint_name = 'lecture_state_pkey' AND column_name = 'lecture_key'
+		) THEN
+			ALTER TABLE lecture_state DROP CONSTRAINT IF EXISTS lecture_state_pkey;
+			ALTER TABLE lecture_state ADD PRIMARY KEY (guild_id, lecture_key);
+		END IF;
+	END $$;`
+
+	_, err = db.Exec(migrateLectureStateKey)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erweitern der lecture_state-Tabelle: %v", err)
+	}
+
+	// Wahlfächer und ihre Teilnehmer
+	createTrackTables := `
+	CREATE TABLE IF NOT EXISTS lecture_tracks (
+		id SERIAL PRIMARY KEY,
+		guild_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		pattern TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(guild_id, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS track_members (
+		guild_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		track_id INTEGER NOT NULL REFERENCES lecture_tracks(id) ON DELETE CASCADE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (guild_id, user_id, track_id)
+	);`
+
+	_, err = db.Exec(createTrackTables)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der Wahlfach-Tabellen: %v", err)
+	}
+
+	// Letzter bekannter Stand des Vorlesungsplans, um Änderungen zu erkennen
+	createCalendarSnapshotTables := `
+	CREATE TABLE IF NOT EXISTS calendar_snapshot (
+		guild_id TEXT NOT NULL,
+		event_uid TEXT NOT NULL,
+		lecture_name TEXT NOT NULL,
+		location TEXT NOT NULL DEFAULT '',
+		lecture_start TIMESTAMPTZ NOT NULL,
+		lecture_end TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (guild_id, event_uid)
+	);
+
+	CREATE TABLE IF NOT EXISTS calendar_snapshot_state (
+		guild_id TEXT PRIMARY KEY,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	_, err = db.Exec(createCalendarSnapshotTables)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der calendar_snapshot-Tabellen: %v", err)
+	}
+
+	// Letzte funktionierende Kopie jedes Kalenders, falls die Quelle nicht erreichbar ist
+	createCalendarCacheTable := `
+	CREATE TABLE IF NOT EXISTS calendar_cache (
+		ical_url TEXT PRIMARY KEY,
+		body BYTEA NOT NULL,
+		etag TEXT NOT NULL DEFAULT '',
+		last_modified TEXT NOT NULL DEFAULT '',
+		fetched_at TIMESTAMPTZ NOT NULL
+	);`
+
+	_, err = db.Exec(createCalendarCacheTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der calendar_cache-Tabelle: %v", err)
+	}
+
+	// Vorlesungserinnerungen und bereits versendete Erinnerungen
+	createReminderTables := `
+	CREATE TABLE IF NOT EXISTS reminder_subscriptions (
+		guild_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		minutes INTEGER NOT NULL,
+		mode TEXT NOT NULL DEFAULT 'dm',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (guild_id, user_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS reminder_log (
+		guild_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		lecture_key TEXT NOT NULL,
+		sent_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (guild_id, user_id, lecture_key)
+	);`
+
+	_, err = db.Exec(createReminderTables)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der Erinnerungs-Tabellen: %v", err)
+	}
+
+	// Studienverlauf: Studium, Semester, Theorie-/Praxisphasen, Prüfungsphasen und Ferien.
+	// Die bisher fest eingetragenen Zeiträume (MGH-TINF23) werden nur zusammen mit der Tabelle
+	// angelegt, damit gelöschte Semester nach einem Neustart nicht wieder auftauchen.
+	createStudyPeriodsTable := `
+	DO $$
+	BEGIN
+		IF to_regclass('study_periods') IS NULL THEN
+			CREATE TABLE study_periods (
+				id SERIAL PRIMARY KEY,
+				guild_id TEXT NOT NULL,
+				kind TEXT NOT NULL,
+				name TEXT NOT NULL,
+				start_date DATE NOT NULL,
+				end_date DATE NOT NULL,
+				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+			);
+
+			INSERT INTO study_periods (guild_id, kind, name, start_date, end_date) VALUES
+				('1181238521734901770', 'studium', 'TINF23', '2023-10-01', '2026-09-30'),
+				('1181238521734901770', 'semester', '4. Semester', '2025-01-06', '2025-03-27'),
+				('1181238521734901770', 'semester', '5. Semester', '2025-09-29', '2025-12-20'),
+				('1181238521734901770', 'semester', '6. Semester', '2026-03-23', '2026-06-14');
+		END IF;
+	END $$;
+
+	CREATE INDEX IF NOT EXISTS idx_study_periods_guild ON study_periods(guild_id, start_date);`
+
+	_, err = db.Exec(createStudyPeriodsTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der study_periods-Tabelle: %v", err)
+	}
+
+	// Klausuren (manuell eingetragen oder aus dem Kalender erkannt)
+	createExamsTable := `
+	CREATE TABLE IF NOT EXISTS exams (
+		id SERIAL PRIMARY KEY,
+		guild_id TEXT NOT NULL,
+		module TEXT NOT NULL,
+		exam_date TIMESTAMPTZ NOT NULL,
+		room TEXT NOT NULL DEFAULT '',
+		source TEXT NOT NULL DEFAULT 'manual',
+		event_uid TEXT,
+		dismissed BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(guild_id, event_uid)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_exams_guild_date ON exams(guild_id, exam_date);`
+
+	_, err = db.Exec(createExamsTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der exams-Tabelle: %v", err)
+	}
+
+	// Persönliche Countdowns der Nutzer
+	createCountdownsTable := `
+	CREATE TABLE IF NOT EXISTS countdowns (
+		id SERIAL PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		target TIMESTAMPTZ NOT NULL,
+		notify BOOLEAN NOT NULL DEFAULT FALSE,
+		notified BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Zeitzone der Gilde, in der der Countdown angelegt wurde, für die Benachrichtigung per DM
+	ALTER TABLE countdowns ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'Europe/Berlin';
+
+	CREATE INDEX IF NOT EXISTS idx_countdowns_user ON countdowns(user_id, target);
+	CREATE INDEX IF NOT EXISTS idx_countdowns_due ON countdowns(target) WHERE notify AND NOT notified;`
+
+	_, err = db.Exec(createCountdownsTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der countdowns-Tabelle: %v", err)
+	}
+
+	// Dauerhaft bearbeitete Nachrichten im Fortschrittskanal
+	createProgressMessagesTable := `
+	CREATE TABLE IF NOT EXISTS progress_messages (
+		guild_id TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		channel_id TEXT NOT NULL,
+		message_id TEXT NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (guild_id, kind)
+	);`
+
+	_, err = db.Exec(createProgressMessagesTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der progress_messages-Tabelle: %v", err)
+	}
+
+	// Anwesenheit über den Einchecken-Button, pro Nutzer und Vorlesung nur einmal
+	createAttendanceTable := `
+	CREATE TABLE IF NOT EXISTS attendance (
+		guild_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		lecture_name TEXT NOT NULL,
+		lecture_start TIMESTAMPTZ NOT NULL,
+		lecture_end TIMESTAMPTZ NOT NULL,
+		checked_in_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (guild_id, user_id, lecture_name, lecture_start)
+	);`
+
+	_, err = db.Exec(createAttendanceTable)
+	if err != nil {
+		return fmt.Errorf("fehler beim Erstellen der attendance-Tabelle: %v", err)
+	}
+
 	return nil
 }
\ No newline at end of file
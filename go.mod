@@ -3,13 +3,13 @@ module discord-bot-go
 go 1.23.4
 
 require (
+	github.com/arran4/golang-ical v0.3.2
 	github.com/bwmarrin/discordgo v0.28.1
 	github.com/joho/godotenv v1.5.1
 	github.com/lib/pq v1.10.9
 )
 
 require (
-	github.com/arran4/golang-ical v0.3.2 // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
 	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b // indirect
 	golang.org/x/sys v0.22.0 // indirect
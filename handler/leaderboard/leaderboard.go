@@ -6,6 +6,8 @@ import (
 	"sort"
 	"time"
 
+	"discord-bot-go/handler/slots"
+
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -28,12 +30,12 @@ func LeaderboardHandler(s *discordgo.Session, m *discordgo.InteractionCreate, db
 	// Daten in einer Rangliste speichern
 	leaderboard := []struct {
 		UserID  string
-		Balance float64
+		Balance int64
 	}{}
 
 	for rows.Next() {
 		var userID string
-		var balance float64
+		var balance int64
 		if err := rows.Scan(&userID, &balance); err != nil {
 			s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
 				Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -46,7 +48,7 @@ func LeaderboardHandler(s *discordgo.Session, m *discordgo.InteractionCreate, db
 		}
 		leaderboard = append(leaderboard, struct {
 			UserID  string
-			Balance float64
+			Balance int64
 		}{
 			UserID:  userID,
 			Balance: balance,
@@ -95,7 +97,7 @@ func LeaderboardHandler(s *discordgo.Session, m *discordgo.InteractionCreate, db
 		}
 
 		username := fmt.Sprintf("<@%s>", entry.UserID)
-		description += fmt.Sprintf("%s %s - %.0f Müller Coins\n", position, username, entry.Balance)
+		description += fmt.Sprintf("%s %s - %s Müller Coins\n", position, username, slots.FormatCoins(entry.Balance))
 	}
 
 	// Embed erstellen
@@ -0,0 +1,348 @@
+package slots
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Startguthaben für neue Konten in Cent
+const startBalance = 1000 * centsPerCoin
+
+// Anzahl der Buchungen, die /history anzeigt
+const historyLimit = 15
+
+// Buchungsarten im Ledger
+const (
+	TxStart       = "start"
+	TxOpening     = "eroeffnung"
+	TxSlotBet     = "slot_einsatz"
+	TxSlotWin     = "slot_gewinn"
+	TxAutoSlotBet = "autoslot_einsatz"
+	TxAutoSlotWin = "autoslot_gewinn"
+	TxRefund      = "erstattung"
+	TxRounding    = "rundung"
+	TxAdminGive   = "admin_give"
+	TxAdminSet    = "admin_set"
+	TxAttendance  = "anwesenheit"
+)
+
+var txLabels = map[string]string{
+	TxStart:       "🎁 Startguthaben",
+	TxOpening:     "📒 Übertrag",
+	TxSlotBet:     "🎰 Slot-Einsatz",
+	TxSlotWin:     "🎰 Slot-Gewinn",
+	TxAutoSlotBet: "🎰 Autoslot-Einsatz",
+	TxAutoSlotWin: "🎰 Autoslot-Gewinn",
+	TxRefund:      "↩️ Erstattung",
+	TxRounding:    "🧮 Rundungsausgleich",
+	// Netto-Buchungen ganzer Spiele aus der Zeit vor den getrennten Einsatz- und Gewinnbuchungen
+	"slot":       "🎰 Slot",
+	"autoslot":   "🎰 Autoslot",
+	TxAdminGive:  "👑 Gutschrift",
+	TxAdminSet:   "👑 Guthaben gesetzt",
+	TxAttendance: "🎓 Anwesenheit",
+}
+
+var errInsufficientFunds = errors.New("nicht genug Guthaben")
+
+type ledgerEntry struct {
+	Amount    int64
+	Kind      string
+	Reference string
+	CreatedAt time.Time
+}
+
+// LedgerMismatch ist ein Konto, dessen Guthaben nicht der Summe seiner Buchungen entspricht
+type LedgerMismatch struct {
+	UserID    string
+	Balance   int64
+	LedgerSum int64
+}
+
+// ensureAccount legt ein Konto mit Startguthaben an, falls es noch nicht existiert
+func ensureAccount(tx *sql.Tx, userID, guildID string) error {
+	result, err := tx.Exec(`INSERT INTO users (user_id, guild_id, balance) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, guild_id) DO NOTHING`, userID, guildID, startBalance)
+	if err != nil {
+		return fmt.Errorf("fehler beim Anlegen des Kontos: %v", err)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if created == 0 {
+		return nil
+	}
+	return writeLedger(tx, userID, guildID, startBalance, TxStart, "")
+}
+
+// createAccount legt ein Konto samt Ledger-Eintrag für das Startguthaben an
+func createAccount(db *sql.DB, userID, guildID string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("fehler beim Starten der Transaktion: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := ensureAccount(tx, userID, guildID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// applyBalanceChange bucht einen Betrag auf ein Konto und schreibt ihn in derselben Transaktion ins Ledger
+func applyBalanceChange(tx *sql.Tx, userID, guildID string, amount int64, kind, reference string) (int64, error) {
+	if err := ensureAccount(tx, userID, guildID); err != nil {
+		return 0, err
+	}
+
+	var balance int64
+	err := tx.QueryRow(`UPDATE users SET balance = balance + $1, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $2 AND guild_id = $3 RETURNING balance`, amount, userID, guildID).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Aktualisieren des Guthabens: %v", err)
+	}
+
+	if err := writeLedger(tx, userID, guildID, amount, kind, reference); err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func writeLedger(tx *sql.Tx, userID, guildID string, amount int64, kind, reference string) error {
+	_, err := tx.Exec(`INSERT INTO transactions (user_id, guild_id, amount, kind, reference)
+		VALUES ($1, $2, $3, $4, $5)`, userID, guildID, amount, kind, reference)
+	if err != nil {
+		return fmt.Errorf("fehler beim Schreiben des Ledgers: %v", err)
+	}
+	return nil
+}
+
+// Credit schreibt einem Benutzer ganze Müller Coins gut, z.B. als Belohnung für die Anwesenheit
+func Credit(db *sql.DB, userID, guildID string, amount int, kind, reference string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("fehler beim Starten der Transaktion: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := CreditTx(tx, userID, guildID, amount, kind, reference); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreditTx bucht wie Credit, aber innerhalb einer Transaktion des Aufrufers, z.B. zusammen
+// mit der Anwesenheit, für die es die Belohnung gibt
+func CreditTx(tx *sql.Tx, userID, guildID string, amount int, kind, reference string) error {
+	cents, err := coinsToCents(amount)
+	if err != nil {
+		return err
+	}
+
+	_, err = applyBalanceChange(tx, userID, guildID, cents, kind, reference)
+	return err
+}
+
+// setBalance setzt das Guthaben auf einen festen Betrag in Cent und bucht die Differenz ins Ledger
+func setBalance(db *sql.DB, userID, guildID string, amount int64, kind, reference string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("fehler beim Starten der Transaktion: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := ensureAccount(tx, userID, guildID); err != nil {
+		return err
+	}
+
+	var balance int64
+	err = tx.QueryRow("SELECT balance FROM users WHERE user_id = $1 AND guild_id = $2 FOR UPDATE", userID, guildID).Scan(&balance)
+	if err != nil {
+		return fmt.Errorf("fehler beim Laden des Guthabens: %v", err)
+	}
+
+	if _, err := applyBalanceChange(tx, userID, guildID, amount-balance, kind, reference); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func loadLedger(db *sql.DB, userID, guildID string, limit int) ([]ledgerEntry, error) {
+	rows, err := db.Query(`SELECT amount, kind, reference, created_at FROM transactions
+		WHERE user_id = $1 AND guild_id = $2 ORDER BY id DESC LIMIT $3`, userID, guildID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Buchungen: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []ledgerEntry
+	for rows.Next() {
+		var entry ledgerEntry
+		if err := rows.Scan(&entry.Amount, &entry.Kind, &entry.Reference, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Buchungen: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// CheckLedger vergleicht das Guthaben jedes Kontos einer Gilde mit der Summe seiner Buchungen
+func CheckLedger(db *sql.DB, guildID string) ([]LedgerMismatch, error) {
+	rows, err := db.Query(`SELECT u.user_id, u.balance, COALESCE(SUM(t.amount), 0) AS ledger_sum
+		FROM users u
+		LEFT JOIN transactions t ON t.user_id = u.user_id AND t.guild_id = u.guild_id
+		WHERE u.guild_id = $1
+		GROUP BY u.user_id, u.balance
+		HAVING u.balance <> COALESCE(SUM(t.amount), 0)
+		ORDER BY u.user_id`, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Abgleich des Ledgers: %v", err)
+	}
+	defer rows.Close()
+
+	var mismatches []LedgerMismatch
+	for rows.Next() {
+		var mismatch LedgerMismatch
+		if err := rows.Scan(&mismatch.UserID, &mismatch.Balance, &mismatch.LedgerSum); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen des Ledger-Abgleichs: %v", err)
+		}
+		mismatches = append(mismatches, mismatch)
+	}
+	return mismatches, rows.Err()
+}
+
+// HistoryCommand zeigt die letzten Buchungen des Benutzers an
+func HistoryCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	// Guthaben gelten pro Server, in DMs gibt es daher keine Buchungen
+	if m.Member == nil {
+		respondEphemeral(s, m, "Dieser Befehl funktioniert nur auf einem Server.")
+		return
+	}
+
+	entries, err := loadLedger(db, m.Member.User.ID, m.GuildID, historyLimit)
+	if err != nil {
+		log.Printf("Fehler bei /history: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden deiner Buchungen.")
+		return
+	}
+	if len(entries) == 0 {
+		respondEphemeral(s, m, "Du hast noch keine Buchungen. Spiele zuerst eine Runde!")
+		return
+	}
+
+	var lines []string
+	for _, entry := range entries {
+		label, ok := txLabels[entry.Kind]
+		if !ok {
+			label = entry.Kind
+		}
+		lines = append(lines, fmt.Sprintf("<t:%d:f> %s **%s**", entry.CreatedAt.Unix(), label, formatSignedCoins(entry.Amount)))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📜 Deine letzten Buchungen",
+		Description: strings.Join(lines, "\n"),
+		Color:       0x00ccff,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// ReconcileCommand prüft, ob alle Guthaben der Gilde mit dem Ledger übereinstimmen
+func ReconcileCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	mismatches, err := CheckLedger(db, m.GuildID)
+	if err != nil {
+		log.Printf("Fehler bei /reconcile: %v", err)
+		respondEphemeral(s, m, "Fehler beim Abgleich des Ledgers.")
+		return
+	}
+	if len(mismatches) == 0 {
+		respondEphemeral(s, m, "✅ Alle Guthaben stimmen mit dem Ledger überein.")
+		return
+	}
+
+	var lines []string
+	for i, mismatch := range mismatches {
+		if i == 20 {
+			lines = append(lines, fmt.Sprintf("... und %d weitere", len(mismatches)-20))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("<@%s>: Guthaben %s, Ledger %s", mismatch.UserID, FormatCoins(mismatch.Balance), FormatCoins(mismatch.LedgerSum)))
+	}
+	respondEphemeral(s, m, fmt.Sprintf("⚠️ %d Konten weichen vom Ledger ab:\n%s", len(mismatches), strings.Join(lines, "\n")))
+}
+
+func respondEphemeral(s *discordgo.Session, m *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// debitBet bucht einen Einsatz in Cent ab, sofern das Guthaben reicht, und liefert das neue Guthaben
+func debitBet(db *sql.DB, userID, guildID string, amount int64, kind, spinID string) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Starten der Transaktion: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := ensureAccount(tx, userID, guildID); err != nil {
+		return 0, err
+	}
+
+	// Die Bedingung im UPDATE verhindert, dass parallele Spiele das Guthaben überziehen
+	var balance int64
+	err = tx.QueryRow(`UPDATE users SET balance = balance - $1, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $2 AND guild_id = $3 AND (balance >= $1 OR $4)
+		RETURNING balance`, amount, userID, guildID, userID == unlimitedPlayerID).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, errInsufficientFunds
+	}
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Abbuchen des Einsatzes: %v", err)
+	}
+
+	if err := writeLedger(tx, userID, guildID, -amount, kind, spinID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("fehler beim Abbuchen des Einsatzes: %v", err)
+	}
+	return balance, nil
+}
+
+// creditSpin schreibt einen Gewinn oder eine Erstattung in Cent gut und liefert das neue Guthaben
+func creditSpin(db *sql.DB, userID, guildID string, amount int64, kind, spinID string) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Starten der Transaktion: %v", err)
+	}
+	defer tx.Rollback()
+
+	balance, err := applyBalanceChange(tx, userID, guildID, amount, kind, spinID)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("fehler beim Gutschreiben: %v", err)
+	}
+	return balance, nil
+}
@@ -0,0 +1,269 @@
+package slots
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Pfad zur Maschinendefinition, ohne Datei wird die eingebaute Standardmaschine verwendet
+const defaultMachineFile = "slot_machine.json"
+
+// Das Spielfeld hat immer 3x3 Felder, jede Gewinnlinie daher genau 3 Positionen
+const boardSize = 3
+
+// machineConfig beschreibt eine Slot-Maschine, wie sie in der JSON-Datei steht
+type machineConfig struct {
+	Name     string                 `json:"name"`
+	Symbols  []symbolConfig         `json:"symbols"`
+	Paylines [][][2]int             `json:"paylines"`
+	Payouts  map[string]json.Number `json:"payouts"`
+}
+
+// symbolConfig ist ein Symbol mit seiner relativen Häufigkeit auf den Walzen
+type symbolConfig struct {
+	Symbol string `json:"symbol"`
+	Weight int    `json:"weight"`
+}
+
+// slotMachine ist eine geprüfte Maschine, die während eines Spiels unverändert bleibt
+type slotMachine struct {
+	config      machineConfig
+	source      string
+	totalWeight int
+	payouts     map[string]int64 // Auszahlungsfaktoren in Zehnteln, siehe linePayout
+}
+
+var activeMachine atomic.Pointer[slotMachine]
+
+func init() {
+	// Die Standardmaschine entspricht den eingebauten Tabellen und muss immer gültig sein
+	machine, err := newSlotMachine(defaultMachineConfig(), "eingebaut")
+	if err != nil {
+		panic(err)
+	}
+	activeMachine.Store(machine)
+}
+
+// currentMachine liefert die aktive Maschine, ein Spiel verwendet sie bis zum Ende
+func currentMachine() *slotMachine {
+	return activeMachine.Load()
+}
+
+// defaultMachineConfig baut die Standardmaschine aus den Tabellen in slot_symbols.go
+func defaultMachineConfig() machineConfig {
+	config := machineConfig{
+		Name:     "Standard",
+		Paylines: lines,
+		Payouts:  payoutFactors,
+	}
+	for i, symbol := range symbols {
+		config.Symbols = append(config.Symbols, symbolConfig{Symbol: symbol, Weight: symbolFrequencies[i]})
+	}
+	return config
+}
+
+func newSlotMachine(config machineConfig, source string) (*slotMachine, error) {
+	if err := validateMachine(config); err != nil {
+		return nil, err
+	}
+
+	payouts, err := parsePayoutFactors(config.Payouts)
+	if err != nil {
+		return nil, err
+	}
+
+	machine := &slotMachine{config: config, source: source, payouts: payouts}
+	for _, symbol := range config.Symbols {
+		machine.totalWeight += symbol.Weight
+	}
+	return machine, nil
+}
+
+// validateMachine prüft Symbole, Gewinnlinien und Auszahlungen einer Maschine
+func validateMachine(config machineConfig) error {
+	if len(config.Symbols) < 2 {
+		return fmt.Errorf("die Maschine braucht mindestens 2 Symbole")
+	}
+	known := make(map[string]bool, len(config.Symbols))
+	for _, symbol := range config.Symbols {
+		if symbol.Symbol == "" {
+			return fmt.Errorf("leeres Symbol")
+		}
+		if known[symbol.Symbol] {
+			return fmt.Errorf("symbol %s ist doppelt definiert", symbol.Symbol)
+		}
+		if symbol.Weight <= 0 {
+			return fmt.Errorf("häufigkeit von %s muss positiv sein: %d", symbol.Symbol, symbol.Weight)
+		}
+		known[symbol.Symbol] = true
+	}
+	// Gewinnkombinationen sind die aneinandergehängten Symbole einer Linie, ein Symbol, das mit
+	// einem anderen beginnt, würde diese Schlüssel mehrdeutig machen
+	for _, a := range config.Symbols {
+		for _, b := range config.Symbols {
+			if a.Symbol != b.Symbol && strings.HasPrefix(b.Symbol, a.Symbol) {
+				return fmt.Errorf("symbol %s ist Anfang von Symbol %s", a.Symbol, b.Symbol)
+			}
+		}
+	}
+
+	if len(config.Paylines) == 0 {
+		return fmt.Errorf("die Maschine braucht mindestens eine Gewinnlinie")
+	}
+	for i, line := range config.Paylines {
+		if len(line) != boardSize {
+			return fmt.Errorf("gewinnlinie %d muss genau %d Positionen haben", i+1, boardSize)
+		}
+		used := make(map[[2]int]bool, len(line))
+		for _, pos := range line {
+			if pos[0] < 0 || pos[0] >= boardSize || pos[1] < 0 || pos[1] >= boardSize {
+				return fmt.Errorf("gewinnlinie %d enthält eine Position außerhalb des Spielfelds: %v", i+1, pos)
+			}
+			if used[pos] {
+				return fmt.Errorf("gewinnlinie %d enthält die Position %v doppelt", i+1, pos)
+			}
+			used[pos] = true
+		}
+	}
+
+	if len(config.Payouts) == 0 {
+		return fmt.Errorf("die Maschine braucht mindestens eine Gewinnkombination")
+	}
+	for combination := range config.Payouts {
+		if !splitsIntoSymbols(combination, config.Symbols, boardSize) {
+			return fmt.Errorf("gewinnkombination %s besteht nicht aus genau %d bekannten Symbolen", combination, boardSize)
+		}
+	}
+	return nil
+}
+
+// splitsIntoSymbols prüft, ob sich eine Kombination in genau count bekannte Symbole zerlegen lässt
+func splitsIntoSymbols(combination string, symbols []symbolConfig, count int) bool {
+	if count == 0 {
+		return combination == ""
+	}
+	for _, symbol := range symbols {
+		rest, found := strings.CutPrefix(combination, symbol.Symbol)
+		if found && splitsIntoSymbols(rest, symbols, count-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// randomSymbol zieht ein Symbol entsprechend der Häufigkeiten
+func (machine *slotMachine) randomSymbol() string {
+	rnd := rand.Intn(machine.totalWeight)
+	cumulative := 0
+	for _, symbol := range machine.config.Symbols {
+		cumulative += symbol.Weight
+		if rnd < cumulative {
+			return symbol.Symbol
+		}
+	}
+
+	return machine.config.Symbols[len(machine.config.Symbols)-1].Symbol
+}
+
+// summary beschreibt die Maschine für Admin-Antworten und Logs
+func (machine *slotMachine) summary() string {
+	return fmt.Sprintf("%s (%s): %d Symbole, %d Gewinnlinien, %d Gewinnkombinationen",
+		machine.config.Name, machine.source, len(machine.config.Symbols), len(machine.config.Paylines), len(machine.config.Payouts))
+}
+
+func machineFile() string {
+	if path := os.Getenv("SLOT_MACHINE_FILE"); path != "" {
+		return path
+	}
+	return defaultMachineFile
+}
+
+// LoadMachine lädt die Maschinendefinition aus der Datei und aktiviert sie. Ohne Datei wird die
+// Standardmaschine verwendet; bei einer ungültigen Datei bleibt die bisherige Maschine aktiv.
+func LoadMachine() (string, error) {
+	path := machineFile()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		machine, err := newSlotMachine(defaultMachineConfig(), "eingebaut")
+		if err != nil {
+			return "", err
+		}
+		activeMachine.Store(machine)
+		return machine.summary(), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("fehler beim Lesen von %s: %v", path, err)
+	}
+
+	var config machineConfig
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return "", fmt.Errorf("fehler beim Parsen von %s: %v", path, err)
+	}
+	if config.Name == "" {
+		config.Name = path
+	}
+
+	machine, err := newSlotMachine(config, path)
+	if err != nil {
+		return "", fmt.Errorf("ungültige Maschine in %s: %v", path, err)
+	}
+	activeMachine.Store(machine)
+	return machine.summary(), nil
+}
+
+// MachineCommand verarbeitet /slotmachine reload|export
+func MachineCommand(s *discordgo.Session, m *discordgo.InteractionCreate) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	switch options[0].Name {
+	case "reload":
+		summary, err := LoadMachine()
+		if err != nil {
+			log.Printf("Fehler bei /slotmachine reload: %v", err)
+			respondEphemeral(s, m, fmt.Sprintf("❌ %v\nDie bisherige Maschine bleibt aktiv: %s", err, currentMachine().summary()))
+			return
+		}
+		log.Printf("🎰 Slot-Maschine neu geladen: %s", summary)
+		respondEphemeral(s, m, "✅ Slot-Maschine geladen: "+summary)
+
+	case "export":
+		machine := currentMachine()
+		data, err := json.MarshalIndent(machine.config, "", "  ")
+		if err != nil {
+			log.Printf("Fehler bei /slotmachine export: %v", err)
+			respondEphemeral(s, m, "Fehler beim Exportieren der Slot-Maschine.")
+			return
+		}
+
+		s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("🎰 %s\nSpeichere die Datei als `%s` und lade sie mit /slotmachine reload.", machine.summary(), machineFile()),
+				Flags:   discordgo.MessageFlagsEphemeral,
+				Files: []*discordgo.File{{
+					Name:        "slot_machine.json",
+					ContentType: "application/json",
+					Reader:      bytes.NewReader(data),
+				}},
+			},
+		})
+
+	default:
+		log.Printf("Unbekannter /slotmachine Unterbefehl: %s", options[0].Name)
+	}
+}
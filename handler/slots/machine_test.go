@@ -0,0 +1,130 @@
+package slots
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// testMachine liefert eine gültige Maschine, die die Testfälle gezielt verändern
+func testMachine() machineConfig {
+	return machineConfig{
+		Name: "Test",
+		Symbols: []symbolConfig{
+			{Symbol: "🍒", Weight: 5},
+			{Symbol: "🔔", Weight: 2},
+		},
+		Paylines: [][][2]int{
+			{{0, 0}, {0, 1}, {0, 2}},
+			{{0, 0}, {1, 1}, {2, 2}},
+		},
+		Payouts: map[string]json.Number{
+			"🍒🍒🍒": "2.5",
+			"🔔🔔🔔": "10",
+		},
+	}
+}
+
+func TestValidateMachine(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(config *machineConfig)
+		wantErr bool
+	}{
+		{name: "gültige Maschine", modify: func(config *machineConfig) {}},
+		{
+			name: "nur ein Symbol",
+			modify: func(config *machineConfig) {
+				config.Symbols = config.Symbols[:1]
+				config.Payouts = map[string]json.Number{"🍒🍒🍒": "2"}
+			},
+			wantErr: true,
+		},
+		{
+			name:    "leeres Symbol",
+			modify:  func(config *machineConfig) { config.Symbols[1].Symbol = "" },
+			wantErr: true,
+		},
+		{
+			name:    "doppeltes Symbol",
+			modify:  func(config *machineConfig) { config.Symbols[1].Symbol = "🍒" },
+			wantErr: true,
+		},
+		{
+			name:    "Häufigkeit null",
+			modify:  func(config *machineConfig) { config.Symbols[0].Weight = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative Häufigkeit",
+			modify:  func(config *machineConfig) { config.Symbols[0].Weight = -1 },
+			wantErr: true,
+		},
+		{
+			name: "Symbol ist Anfang eines anderen",
+			modify: func(config *machineConfig) {
+				config.Symbols = append(config.Symbols, symbolConfig{Symbol: "🍒🍒", Weight: 1})
+			},
+			wantErr: true,
+		},
+		{
+			name:    "keine Gewinnlinie",
+			modify:  func(config *machineConfig) { config.Paylines = nil },
+			wantErr: true,
+		},
+		{
+			name:    "Gewinnlinie zu kurz",
+			modify:  func(config *machineConfig) { config.Paylines[0] = config.Paylines[0][:2] },
+			wantErr: true,
+		},
+		{
+			name:    "Position außerhalb des Spielfelds",
+			modify:  func(config *machineConfig) { config.Paylines[1][2] = [2]int{3, 0} },
+			wantErr: true,
+		},
+		{
+			name:    "negative Position",
+			modify:  func(config *machineConfig) { config.Paylines[1][0] = [2]int{0, -1} },
+			wantErr: true,
+		},
+		{
+			name:    "doppelte Position",
+			modify:  func(config *machineConfig) { config.Paylines[0][2] = [2]int{0, 0} },
+			wantErr: true,
+		},
+		{
+			name:    "keine Gewinnkombination",
+			modify:  func(config *machineConfig) { config.Payouts = nil },
+			wantErr: true,
+		},
+		{
+			name:    "Kombination mit unbekanntem Symbol",
+			modify:  func(config *machineConfig) { config.Payouts["🍋🍋🍋"] = "3" },
+			wantErr: true,
+		},
+		{
+			name:    "Kombination aus zwei Symbolen",
+			modify:  func(config *machineConfig) { config.Payouts["🍒🍒"] = "3" },
+			wantErr: true,
+		},
+		{
+			name:    "gemischte Kombination",
+			modify:  func(config *machineConfig) { config.Payouts["🍒🔔🍒"] = "1" },
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testMachine()
+			tt.modify(&config)
+
+			err := validateMachine(config)
+			if tt.wantErr && err == nil {
+				t.Error("validateMachine() ohne Fehler, erwartet Fehler")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateMachine(): %v", err)
+			}
+		})
+	}
+}
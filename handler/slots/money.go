@@ -0,0 +1,105 @@
+package slots
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Guthaben und Buchungen werden in Cent (1/100 Müller Coin) gespeichert, damit keine
+// Rundungsfehler durch Gleitkommazahlen entstehen. Einsätze sind immer ganze Coins.
+const centsPerCoin = 100
+
+// MaxBet ist der höchste Einsatz pro Spiel, damit Einsatz und Gewinn sicher in int64 passen
+const MaxBet = 1_000_000
+
+var errAmountTooLarge = errors.New("betrag zu groß")
+
+// coinsToCents rechnet ganze Müller Coins in Cent um
+func coinsToCents(coins int) (int64, error) {
+	if int64(coins) > math.MaxInt64/centsPerCoin || int64(coins) < math.MinInt64/centsPerCoin {
+		return 0, errAmountTooLarge
+	}
+	return int64(coins) * centsPerCoin, nil
+}
+
+// parseFactorTenths liefert einen Auszahlungsfaktor aus der Maschinendatei in Zehnteln,
+// z.B. 384.1 -> 3841. Der Wert wird als Text ausgewertet, damit keine Gleitkommazahl
+// in die Geldberechnung gelangt.
+func parseFactorTenths(factor json.Number) (int64, error) {
+	whole, fraction, _ := strings.Cut(factor.String(), ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > 1 {
+		return 0, fmt.Errorf("mehr als eine Nachkommastelle: %s", factor)
+	}
+
+	// Nur Ziffern zulassen, Vorzeichen und Exponenten sind keine gültigen Faktoren
+	for _, part := range []string{whole, fraction} {
+		if strings.Trim(part, "0123456789") != "" {
+			return 0, fmt.Errorf("ungültiger Faktor: %s", factor)
+		}
+	}
+	if whole == "" {
+		return 0, fmt.Errorf("ungültiger Faktor: %s", factor)
+	}
+
+	tenths, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || tenths > math.MaxInt64/10 {
+		return 0, fmt.Errorf("faktor zu groß: %s", factor)
+	}
+	if fraction == "" {
+		tenths *= 10
+	}
+	if tenths == 0 {
+		return 0, fmt.Errorf("faktor muss positiv sein: %s", factor)
+	}
+	return tenths, nil
+}
+
+// linePayout berechnet den Gewinn einer Linie in Cent aus einem Faktor in Zehnteln. Da
+// Einsätze ganze Coins sind, ist das Ergebnis immer exakt; ein Rest unter einem Cent würde
+// zugunsten der Bank abgerundet.
+func linePayout(betCents, factorTenths int64) (int64, error) {
+	if betCents < 0 || factorTenths < 0 || (factorTenths > 0 && betCents > math.MaxInt64/factorTenths) {
+		return 0, errAmountTooLarge
+	}
+	return betCents * factorTenths / 10, nil
+}
+
+// parsePayoutFactors wandelt alle Auszahlungsfaktoren einer Maschine in Zehntel um
+func parsePayoutFactors(factors map[string]json.Number) (map[string]int64, error) {
+	payouts := make(map[string]int64, len(factors))
+	for combination, factor := range factors {
+		tenths, err := parseFactorTenths(factor)
+		if err != nil {
+			return nil, fmt.Errorf("auszahlungsfaktor für %s: %v", combination, err)
+		}
+		payouts[combination] = tenths
+	}
+	return payouts, nil
+}
+
+// FormatCoins formatiert einen Betrag in Cent als Müller Coins, Nachkommastellen nur bei Bedarf
+func FormatCoins(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	if cents%centsPerCoin == 0 {
+		return fmt.Sprintf("%s%d", sign, cents/centsPerCoin)
+	}
+	return fmt.Sprintf("%s%d,%02d", sign, cents/centsPerCoin, cents%centsPerCoin)
+}
+
+// formatSignedCoins formatiert eine Buchung immer mit Vorzeichen
+func formatSignedCoins(cents int64) string {
+	formatted := FormatCoins(cents)
+	if !strings.HasPrefix(formatted, "-") {
+		formatted = "+" + formatted
+	}
+	return formatted
+}
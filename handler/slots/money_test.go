@@ -0,0 +1,90 @@
+package slots
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseFactorTenths(t *testing.T) {
+	tests := []struct {
+		factor  json.Number
+		want    int64
+		wantErr bool
+	}{
+		{factor: "1", want: 10},
+		{factor: "384.1", want: 3841},
+		{factor: "2.5", want: 25},
+		{factor: "1.50", want: 15},
+		{factor: "0.1", want: 1},
+		{factor: "007", want: 70},
+		{factor: "0", wantErr: true},
+		{factor: "0.0", wantErr: true},
+		{factor: "1.25", wantErr: true},
+		{factor: "-1", wantErr: true},
+		{factor: "+1", wantErr: true},
+		{factor: "1e2", wantErr: true},
+		{factor: ".5", wantErr: true},
+		{factor: "", wantErr: true},
+		{factor: "1.x", wantErr: true},
+		{factor: "922337203685477580", want: 9223372036854775800},
+		{factor: "922337203685477581", wantErr: true},
+		{factor: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.factor), func(t *testing.T) {
+			got, err := parseFactorTenths(tt.factor)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseFactorTenths(%q) = %d, erwartet Fehler", tt.factor, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseFactorTenths(%q): %v", tt.factor, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseFactorTenths(%q) = %d, erwartet %d", tt.factor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinePayout(t *testing.T) {
+	tests := []struct {
+		name         string
+		betCents     int64
+		factorTenths int64
+		want         int64
+		wantErr      bool
+	}{
+		{name: "ganzer Faktor", betCents: 100, factorTenths: 20, want: 200},
+		{name: "Zehntelfaktor", betCents: 100, factorTenths: 3841, want: 38410},
+		{name: "Rest wird abgerundet", betCents: 1, factorTenths: 15, want: 1},
+		{name: "höchster Einsatz", betCents: MaxBet * centsPerCoin, factorTenths: 3841, want: MaxBet * centsPerCoin * 3841 / 10},
+		{name: "kein Einsatz", betCents: 0, factorTenths: 25, want: 0},
+		{name: "Faktor null", betCents: 100, factorTenths: 0, want: 0},
+		{name: "Überlauf", betCents: math.MaxInt64 / 2, factorTenths: 3, wantErr: true},
+		{name: "negativer Einsatz", betCents: -100, factorTenths: 10, wantErr: true},
+		{name: "negativer Faktor", betCents: 100, factorTenths: -10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := linePayout(tt.betCents, tt.factorTenths)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("linePayout(%d, %d) = %d, erwartet Fehler", tt.betCents, tt.factorTenths, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("linePayout(%d, %d): %v", tt.betCents, tt.factorTenths, err)
+			}
+			if got != tt.want {
+				t.Errorf("linePayout(%d, %d) = %d, erwartet %d", tt.betCents, tt.factorTenths, got, tt.want)
+			}
+		})
+	}
+}
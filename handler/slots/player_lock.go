@@ -0,0 +1,129 @@
+package slots
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Solange ein Spiel läuft, wird die Sperre regelmäßig verlängert (auch ein langes /autoslot).
+// Stürzt der Bot mitten im Spiel ab, ist der Spieler spätestens nach playerLockTTL wieder frei.
+const (
+	playerLockTTL   = time.Minute
+	playerLockRenew = playerLockTTL / 3
+)
+
+// instanceID unterscheidet die Locks mehrerer Bot-Instanzen
+var instanceID = newInstanceID()
+
+// Lokale Sperre, damit Doppelklicks nicht erst an der Datenbank scheitern
+var (
+	activePlayersMu sync.Mutex
+	activePlayers   = make(map[string]bool)
+)
+
+func newInstanceID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// playerLock ist die Spielsperre eines Benutzers in einer Gilde
+type playerLock struct {
+	db      *sql.DB
+	userID  string
+	guildID string
+	owner   string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func playerKey(userID, guildID string) string {
+	return guildID + "/" + userID
+}
+
+// acquirePlayerLock sperrt den Benutzer lokal und in der Datenbank. Liefert nil, wenn er bereits spielt.
+// Abgelaufene Sperren werden übernommen, die Ablaufzeit richtet sich nach der Uhr der Datenbank.
+func acquirePlayerLock(db *sql.DB, userID, guildID, gameID string) (*playerLock, error) {
+	key := playerKey(userID, guildID)
+
+	activePlayersMu.Lock()
+	if activePlayers[key] {
+		activePlayersMu.Unlock()
+		return nil, nil
+	}
+	activePlayers[key] = true
+	activePlayersMu.Unlock()
+
+	lock := &playerLock{db: db, userID: userID, guildID: guildID, owner: instanceID + "/" + gameID}
+
+	var owner string
+	err := db.QueryRow(`INSERT INTO player_locks (user_id, guild_id, owner, expires_at)
+		VALUES ($1, $2, $3, now() + $4 * INTERVAL '1 second')
+		ON CONFLICT (user_id, guild_id) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE player_locks.expires_at < now()
+		RETURNING owner`, userID, guildID, lock.owner, int(playerLockTTL.Seconds())).Scan(&owner)
+	if err == sql.ErrNoRows {
+		lock.releaseLocal()
+		return nil, nil
+	}
+	if err != nil {
+		lock.releaseLocal()
+		return nil, fmt.Errorf("fehler beim Sperren des Spielers: %v", err)
+	}
+
+	lock.stop = make(chan struct{})
+	lock.done = make(chan struct{})
+	go lock.keepAlive()
+	return lock, nil
+}
+
+// keepAlive verlängert die Sperre, bis Release aufgerufen wird
+func (l *playerLock) keepAlive() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(playerLockRenew)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_, err := l.db.Exec(`UPDATE player_locks SET expires_at = now() + $4 * INTERVAL '1 second'
+				WHERE user_id = $1 AND guild_id = $2 AND owner = $3`,
+				l.userID, l.guildID, l.owner, int(playerLockTTL.Seconds()))
+			if err != nil {
+				log.Printf("Fehler beim Verlängern der Spielsperre: %v", err)
+			}
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Release gibt die Sperre wieder frei, eine inzwischen übernommene Sperre bleibt unberührt
+func (l *playerLock) Release() {
+	defer l.releaseLocal()
+
+	// Erst die Verlängerung beenden, damit sie die gelöschte Sperre nicht mehr anfasst
+	close(l.stop)
+	<-l.done
+
+	_, err := l.db.Exec("DELETE FROM player_locks WHERE user_id = $1 AND guild_id = $2 AND owner = $3",
+		l.userID, l.guildID, l.owner)
+	if err != nil {
+		// Die Sperre läuft spätestens nach playerLockTTL ab
+		log.Printf("Fehler beim Freigeben der Spielsperre: %v", err)
+	}
+}
+
+func (l *playerLock) releaseLocal() {
+	activePlayersMu.Lock()
+	delete(activePlayers, playerKey(l.userID, l.guildID))
+	activePlayersMu.Unlock()
+}
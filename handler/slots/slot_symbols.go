@@ -1,80 +1,83 @@
 package slots
 
+import "encoding/json"
+
+// Symbole, Häufigkeiten und Auszahlungen der Standardmaschine, siehe defaultMachineConfig
 var (
 	symbols = []string{"❌", "❓", "🍒", "🍋", "🍊", "🍇", "⭐", "💎", "💰"}
 	symbolFrequencies = []int{9, 15, 18, 17, 13, 11, 7, 3, 1}
-	payoutFactors = map[string]float32{
+	payoutFactors = map[string]json.Number{
 
         // Joker Kombinationen
-        "❓⭐⭐": 2.5,
-        "❓💰💰": 5.8,
-        "❓🍇🍇": 1.7,
-        "❓❓❓": 1.7,
-        "❓🍋🍋": 0.7,
-        "❓💎💎": 3.6,
-        "❓🍊🍊": 0.9,
-        "❓🍒🍒": 0.4,
-        "❓💰❓": 3.4,
-        "❓❓💰": 3.1,
+        "❓⭐⭐": "2.5",
+        "❓💰💰": "5.8",
+        "❓🍇🍇": "1.7",
+        "❓❓❓": "1.7",
+        "❓🍋🍋": "0.7",
+        "❓💎💎": "3.6",
+        "❓🍊🍊": "0.9",
+        "❓🍒🍒": "0.4",
+        "❓💰❓": "3.4",
+        "❓❓💰": "3.1",
 
         // Standard Kombinationen
-        "💰💰💰": 384.1,
-        "💰🍒💰": 9.1,
-        "💰💰🍋": 8.4,
-        "🍋🍋❓": 0.5,
-        "🍇🍇🍇": 6.5,
-        "🍇🍇❓": 1.2,
-        "🍊💰💰": 10.9,
-        "🍊🍊🍊": 3.1,
-        "🍒❓🍒": 0.4,
-        "💰🍋💰": 8.5,
-        "⭐⭐💰": 15,
-        "💰❓❓": 2.8,
-        "🍒🍒❓": 0.4,
-        "💰❓💰": 6.4,
-        "💰💰🍊": 11.7,
-        "⭐⭐❓": 2.3,
-        "🍊🍊❓": 0.9,
-        "💰🍇💰": 13.3,
-        "🍋💰💰": 8.8,
-        "⭐💰💰": 20.1,
-        "💰💰🍇": 15.8,
-        "🍋🍋💰": 5,
-        "🍋💰🍋": 4.2,
-        "💎❓💎": 4.3,
-        "💰⭐💰": 24,
-        "💰⭐⭐": 21.3,
-        "🍒🍒💰": 3.9,
-        "⭐❓⭐": 2.3,
-        "💰🍇🍇": 10.2,
-        "💰💰❓": 6.1,
-        "💎💰💎": 43.9,
-        "💰🍊💰": 11.7,
-        "🍒💰💰": 7.5,
-        "💰💎💰": 61.9,
-        "💰💰⭐": 22.9,
-        "🍊💰🍊": 8,
-        "🍒💰🍒": 3.7,
-        "🍊❓🍊": 0.9,
-        "⭐⭐⭐": 14.9,
-        "⭐💰⭐": 19.3,
-        "💎💎❓": 4.4,
-        "💎💎💎": 30.5,
-        "💰🍊🍊": 6.8,
-        "💰🍋🍋": 5.1,
-        "🍇💰💰": 16.2,
-        "🍊🍊💰": 5.5,
-        "💰💎💎": 45.4,
-        "💎💎💰": 42.5,
-        "🍒🍒🍒": 1.5,
-        "💰💰💎": 68.1,
-        "💰💰🍒": 6.7,
-        "🍇🍇💰": 11.7,
-        "💎💰💰": 61.4,
-        "🍇💰🍇": 10.8,
-        "💰🍒🍒": 3.8,
-        "🍋🍋🍋": 2,
-        "🍋❓🍋": 0.6,
-        "🍇❓🍇": 1.8,
+        "💰💰💰": "384.1",
+        "💰🍒💰": "9.1",
+        "💰💰🍋": "8.4",
+        "🍋🍋❓": "0.5",
+        "🍇🍇🍇": "6.5",
+        "🍇🍇❓": "1.2",
+        "🍊💰💰": "10.9",
+        "🍊🍊🍊": "3.1",
+        "🍒❓🍒": "0.4",
+        "💰🍋💰": "8.5",
+        "⭐⭐💰": "15",
+        "💰❓❓": "2.8",
+        "🍒🍒❓": "0.4",
+        "💰❓💰": "6.4",
+        "💰💰🍊": "11.7",
+        "⭐⭐❓": "2.3",
+        "🍊🍊❓": "0.9",
+        "💰🍇💰": "13.3",
+        "🍋💰💰": "8.8",
+        "⭐💰💰": "20.1",
+        "💰💰🍇": "15.8",
+        "🍋🍋💰": "5",
+        "🍋💰🍋": "4.2",
+        "💎❓💎": "4.3",
+        "💰⭐💰": "24",
+        "💰⭐⭐": "21.3",
+        "🍒🍒💰": "3.9",
+        "⭐❓⭐": "2.3",
+        "💰🍇🍇": "10.2",
+        "💰💰❓": "6.1",
+        "💎💰💎": "43.9",
+        "💰🍊💰": "11.7",
+        "🍒💰💰": "7.5",
+        "💰💎💰": "61.9",
+        "💰💰⭐": "22.9",
+        "🍊💰🍊": "8",
+        "🍒💰🍒": "3.7",
+        "🍊❓🍊": "0.9",
+        "⭐⭐⭐": "14.9",
+        "⭐💰⭐": "19.3",
+        "💎💎❓": "4.4",
+        "💎💎💎": "30.5",
+        "💰🍊🍊": "6.8",
+        "💰🍋🍋": "5.1",
+        "🍇💰💰": "16.2",
+        "🍊🍊💰": "5.5",
+        "💰💎💎": "45.4",
+        "💎💎💰": "42.5",
+        "🍒🍒🍒": "1.5",
+        "💰💰💎": "68.1",
+        "💰💰🍒": "6.7",
+        "🍇🍇💰": "11.7",
+        "💎💰💰": "61.4",
+        "🍇💰🍇": "10.8",
+        "💰🍒🍒": "3.8",
+        "🍋🍋🍋": "2",
+        "🍋❓🍋": "0.6",
+        "🍇❓🍇": "1.8",
 
 	})
\ No newline at end of file
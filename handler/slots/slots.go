@@ -3,6 +3,8 @@ package slots
 import (
 	"database/sql"
 	"fmt"
+	"log"
+	"math"
 	"math/rand"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// Gewinnlinien der Standardmaschine
 var (
 	lines = [][][2]int{
 		{{0, 0}, {0, 1}, {0, 2}}, // Horizontal oben
@@ -23,45 +26,16 @@ var (
 	}
 )
 
-var activePlayers = make(map[string]bool)
+// Anzahl der Runden bei /autoslot
+const autoSlotRounds = 10
 
-func isUserPlaying(userID string) bool {
-	// Überprüft, ob der Benutzer gerade spielt
-	playing, exists := activePlayers[userID]
-	return exists && playing
-}
-
-func setUserPlaying(userID string, playing bool) {
-	// Setzt den Spielstatus eines Benutzers
-	if playing {
-		activePlayers[userID] = true
-	} else {
-		delete(activePlayers, userID)
-	}
-}
+// Dieser Benutzer darf auch ohne ausreichendes Guthaben spielen
+const unlimitedPlayerID = "423480294948208661"
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
-func getRandomSymbol() string {
-	total := 0
-	for _, freq := range symbolFrequencies {
-		total += freq
-	}
-
-	rnd := rand.Intn(total)
-	cumulative := 0
-	for i, freq := range symbolFrequencies {
-		cumulative += freq
-		if rnd < cumulative {
-			return symbols[i]
-		}
-	}
-
-	return symbols[len(symbols)-1]
-}
-
 // Initialisiere das leere Slot-Board
 func initializeSlotBoard() [][]string {
 	return [][]string{
@@ -72,12 +46,12 @@ func initializeSlotBoard() [][]string {
 }
 
 // Simulation einer einzelnen Slot-Maschine-Drehung
-func spinSlotMachine() [][]string {
+func spinSlotMachine(machine *slotMachine) [][]string {
 	newBoard := make([][]string, 3) // Neues Board erstellen
 	for i := 0; i < 3; i++ {
 		newBoard[i] = make([]string, 3)
 		for j := 0; j < 3; j++ {
-			newBoard[i][j] = getRandomSymbol() // Jedes Symbol neu generieren
+			newBoard[i][j] = machine.randomSymbol() // Jedes Symbol neu generieren
 		}
 	}
 	return newBoard
@@ -110,12 +84,18 @@ func convertToFixedArray(board [][]string) [3][3]string {
 	return fixedBoard
 }
 
-func calculatePayoutWithCombinations(board [3][3]string, bet int) (float32, []string) {
-    var payout float32 = 0
+// calculatePayoutWithCombinations liefert den Gewinn in Cent, siehe linePayout für die Rundung
+func calculatePayoutWithCombinations(machine *slotMachine, board [3][3]string, bet int) (int64, []string, error) {
+    betCents, err := coinsToCents(bet)
+    if err != nil {
+        return 0, nil, err
+    }
+
+    var payout int64 = 0
     winningLinesMap := make(map[string]bool)
     var winningLines []string
 
-    for _, line := range lines {
+    for _, line := range machine.config.Paylines {
         var symbols []string
         var lineKeyParts []string
 
@@ -127,150 +107,90 @@ func calculatePayoutWithCombinations(board [3][3]string, bet int) (float32, []st
         lineKey := strings.Join(lineKeyParts, ",")
         formattedLine := strings.Join(symbols, "")
 
-        if factor, exists := payoutFactors[formattedLine]; exists {
+        if factor, exists := machine.payouts[formattedLine]; exists {
             if !winningLinesMap[lineKey] {
-                payout += float32(bet) * factor
+                amount, err := linePayout(betCents, factor)
+                if err != nil || payout > math.MaxInt64-amount {
+                    return 0, nil, errAmountTooLarge
+                }
+                payout += amount
                 winningLinesMap[lineKey] = true
                 winningLines = append(winningLines, formattedLine)
             }
         }
     }
 
-    return payout, winningLines
+    return payout, winningLines, nil
 }
 
-func MoneyAll(s *discordgo.Session, db *sql.DB, guildID string, amount int) error {
+func MoneyAll(s *discordgo.Session, db *sql.DB, guildID string, amount int, adminID string) error {
 	// Alle Mitglieder der Gilde abfragen
 	members, err := s.GuildMembers(guildID, "", 1000)
 	if err != nil {
 		return fmt.Errorf("fehler beim Abrufen der Gildenmitglieder: %v", err)
 	}
 
-	for _, member := range members {
-		userID := member.User.ID
-
-		// Überprüfen, ob der Benutzer bereits in der Datenbank existiert
-		var exists bool
-		err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1 AND guild_id = $2)", userID, guildID).Scan(&exists)
-		if err != nil {
-			return fmt.Errorf("fehler beim Überprüfen der Datenbank: %v", err)
-		}
+	cents, err := coinsToCents(amount)
+	if err != nil {
+		return err
+	}
 
-		if exists {
-			// Wenn der Benutzer existiert, setze den Betrag (nicht addieren)
-			_, err := db.Exec("UPDATE users SET balance = $1 WHERE user_id = $2 AND guild_id = $3", amount, userID, guildID)
-			if err != nil {
-				return fmt.Errorf("fehler beim Aktualisieren des Guthabens: %v", err)
-			}
-		} else {
-			// Wenn der Benutzer nicht existiert, füge ihn hinzu
-			_, err := db.Exec("INSERT INTO users (user_id, guild_id, balance) VALUES ($1, $2, $3)", userID, guildID, amount)
-			if err != nil {
-				return fmt.Errorf("fehler beim Hinzufügen eines neuen Benutzers: %v", err)
-			}
+	for _, member := range members {
+		// Betrag setzen (nicht addieren), die Differenz landet im Ledger
+		if err := setBalance(db, member.User.ID, guildID, cents, TxAdminSet, adminID); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func MoneyGive(db *sql.DB, userID string, guildID string, amount int) error {
-	// Erst prüfen, ob der Benutzer existiert
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1 AND guild_id = $2)", userID, guildID).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("fehler beim Überprüfen der Datenbank: %v", err)
-	}
-
-	if exists {
-		// Benutzer existiert, Guthaben aktualisieren
-		_, err = db.Exec("UPDATE users SET balance = balance + $1 WHERE user_id = $2 AND guild_id = $3", amount, userID, guildID)
-		return err
-	} else {
-		// Benutzer existiert nicht, neuen Benutzer mit Startguthaben + Betrag erstellen
-		_, err = db.Exec("INSERT INTO users (user_id, guild_id, balance) VALUES ($1, $2, $3)", userID, guildID, 1000+amount)
-		return err
-	}
+func MoneyGive(db *sql.DB, userID string, guildID string, amount int, adminID string) error {
+	// Neue Benutzer erhalten zuerst das Startguthaben, danach wird der Betrag gutgeschrieben
+	return Credit(db, userID, guildID, amount, TxAdminGive, adminID)
 }
 
 func SlotCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB, bet int) {
-	// Prüfen, ob der Benutzer bereits spielt
-	if isUserPlaying(m.Member.User.ID) {
-		s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "Du spielst bereits ein Spiel! Bitte warte, bis es beendet ist.",
-				Flags: discordgo.MessageFlagsEphemeral,
-			},
-		})
+	// Benutzer als spielend markieren, die Sperre gilt auch über mehrere Bot-Instanzen hinweg
+	lock, err := acquirePlayerLock(db, m.Member.User.ID, m.GuildID, m.ID)
+	if err != nil {
+		log.Printf("Fehler beim Sperren des Spielers: %v", err)
+		respondEphemeral(s, m, "Fehler beim Starten des Spiels. Bitte versuche es später erneut.")
 		return
 	}
-
-	// Benutzer als spielend markieren
-	setUserPlaying(m.Member.User.ID, true)
-	defer setUserPlaying(m.Member.User.ID, false)
-	
-	// Balance Überprüfen - mit guild_id für bessere Datenkonsistenz
-	var balance float32
-	err := db.QueryRow("SELECT balance FROM users WHERE user_id = $1 AND guild_id = $2", m.Member.User.ID, m.GuildID).Scan(&balance)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			// Benutzer existiert nicht, erstelle ihn mit Startguthaben
-			_, err = db.Exec("INSERT INTO users (user_id, guild_id, balance) VALUES ($1, $2, $3)", m.Member.User.ID, m.GuildID, 1000)
-			if err != nil {
-				s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Content: "Fehler beim Erstellen des Benutzerkontos.",
-						Flags: discordgo.MessageFlagsEphemeral,
-					},
-				})
-				return
-			}
-			balance = 1000
-		} else {
-			s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "Fehler: Benutzer konnte nicht gefunden werden.",
-					Flags: discordgo.MessageFlagsEphemeral,
-				},
-			})
-			return
-		}
+	if lock == nil {
+		respondEphemeral(s, m, "Du spielst bereits ein Spiel! Bitte warte, bis es beendet ist.")
+		return
 	}
+	defer lock.Release()
 
 	if bet < 1 {
-		s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: "Der Betrag zum spielen muss mehr als 0 sein.",
-				Flags: discordgo.MessageFlagsEphemeral,
-			},
-		})
+		respondEphemeral(s, m, "Der Betrag zum spielen muss mehr als 0 sein.")
+		return
+	}
+	if bet > MaxBet {
+		respondEphemeral(s, m, fmt.Sprintf("Der Einsatz darf höchstens %d betragen.", MaxBet))
 		return
 	}
+	// Kann nach der Prüfung gegen MaxBet nicht überlaufen
+	betCents, _ := coinsToCents(bet)
 
-	if m.Member.User.ID != "423480294948208661" {
-		if balance < float32(bet) {
-			s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "Nicht genug Spielgeld.",
-					Flags: discordgo.MessageFlagsEphemeral,
-				},
-			})
-			return
-		}
+	// Einsatz vorab abbuchen, damit während der Animation kein Guthaben doppelt verspielt werden kann
+	balance, err := debitBet(db, m.Member.User.ID, m.GuildID, betCents, TxSlotBet, m.ID)
+	if err == errInsufficientFunds {
+		respondEphemeral(s, m, "Nicht genug Spielgeld.")
+		return
+	}
+	if err != nil {
+		log.Printf("Fehler beim Abbuchen des Einsatzes: %v", err)
+		respondEphemeral(s, m, "Fehler beim Abbuchen des Einsatzes. Bitte versuche es später erneut.")
+		return
 	}
 
-	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Content: fmt.Sprintf("Du spielst mit: %d", bet),
-			Flags: discordgo.MessageFlagsEphemeral,
-		},
-	})
+	respondEphemeral(s, m, fmt.Sprintf("Du spielst mit: %d", bet))
+
+	// Ein Neuladen der Maschine wirkt sich erst auf das nächste Spiel aus
+	machine := currentMachine()
 
 	// Initiale Slot-Maschine anzeigen
 	board := initializeSlotBoard()
@@ -280,23 +200,39 @@ func SlotCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.D
 		Color:       0x00ccff,
 		Timestamp:   time.Now().Format(time.RFC3339),
 	}
-	msg, _ := s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	msg, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	if err != nil {
+		// Ohne Nachricht sieht der Spieler kein Ergebnis, daher den Einsatz zurückbuchen
+		log.Printf("Fehler beim Senden der Slot-Maschine: %v", err)
+		if _, err := creditSpin(db, m.Member.User.ID, m.GuildID, betCents, TxRefund, m.ID); err != nil {
+			log.Printf("Fehler beim Erstatten des Einsatzes: %v", err)
+		}
+		return
+	}
 
 	// Animation der Slot-Maschine
 	for i := 1; i <= 4; i++ {
-		board = spinSlotMachine()
+		board = spinSlotMachine(machine)
 		embed.Description = fmt.Sprintf("%s spielt gerade!\n\n%s", fmt.Sprintf("<@%s>", m.Member.User.ID), formatSlotBoard(board))
 		s.ChannelMessageEditEmbed(m.ChannelID, msg.ID, embed)
 		time.Sleep(1 * time.Second)
 	}
 
-	// Gewinn berechnen
+	// Gewinn berechnen und gutschreiben, die Interaktions-ID dient als Spin-ID im Ledger
 	fixedBoard := convertToFixedArray(board)
-	payout, winningLines := calculatePayoutWithCombinations(fixedBoard, bet)
-	if payout > 0 {
-		db.Exec("UPDATE users SET balance = balance + $1 WHERE user_id = $2 AND guild_id = $3", payout-float32(bet), m.Member.User.ID, m.GuildID)
-	} else {
-		db.Exec("UPDATE users SET balance = balance - $1 WHERE user_id = $2 AND guild_id = $3", bet, m.Member.User.ID, m.GuildID)
+	payout, winningLines, err := calculatePayoutWithCombinations(machine, fixedBoard, bet)
+	balanceText := FormatCoins(balance)
+	if err != nil {
+		log.Printf("Fehler beim Berechnen des Gewinns: %v", err)
+		balanceText = "⚠️ Gewinn konnte nicht gutgeschrieben werden, bitte melde dich bei einem Admin."
+	} else if payout > 0 {
+		balance, err = creditSpin(db, m.Member.User.ID, m.GuildID, payout, TxSlotWin, m.ID)
+		if err != nil {
+			log.Printf("Fehler beim Gutschreiben des Gewinns: %v", err)
+			balanceText = "⚠️ Gewinn konnte nicht gutgeschrieben werden, bitte melde dich bei einem Admin."
+		} else {
+			balanceText = FormatCoins(balance)
+		}
 	}
 
 	// Ergebnis-Embed
@@ -312,7 +248,7 @@ func SlotCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.D
 			},
 			{
 				Name:   "Gewinn",
-				Value:  fmt.Sprintf("%.0f", payout),
+				Value:  FormatCoins(payout),
 				Inline: true,
 			},
 			{
@@ -322,7 +258,7 @@ func SlotCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.D
 			},
 			{
 				Name:   "Neuer Kontostand",
-				Value:  fmt.Sprintf("%.0f", float32(balance)+payout-float32(bet)),
+				Value:  balanceText,
 				Inline: false,
 			},
 		},
@@ -331,148 +267,127 @@ func SlotCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.D
 	s.ChannelMessageEditEmbed(m.ChannelID, msg.ID, resultEmbed)
 }
 
-func GetUserBalance(db *sql.DB, userID string, guildID string) (float64, error) {
-	var balance float64
+// GetUserBalance liefert das Guthaben in Cent
+func GetUserBalance(db *sql.DB, userID string, guildID string) (int64, error) {
+	var balance int64
 	err := db.QueryRow("SELECT balance FROM users WHERE user_id = $1 AND guild_id = $2", userID, guildID).Scan(&balance)
 	if err == sql.ErrNoRows {
 		// Benutzer existiert nicht, erstelle ihn mit Startguthaben
-		_, err = db.Exec("INSERT INTO users (user_id, guild_id, balance) VALUES ($1, $2, $3)", userID, guildID, 1000)
-		if err != nil {
+		if err := createAccount(db, userID, guildID); err != nil {
 			return 0, err
 		}
-		return 1000, nil
+		return startBalance, nil
 	}
 	return balance, err
 }
 
 func AutoSlotCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB, bet int) {
-    // Prüfen, ob der Benutzer bereits spielt
-    if isUserPlaying(m.Member.User.ID) {
-        s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-            Type: discordgo.InteractionResponseChannelMessageWithSource,
-            Data: &discordgo.InteractionResponseData{
-                Content: "Du spielst bereits ein Spiel! Bitte warte, bis es beendet ist.",
-                Flags: discordgo.MessageFlagsEphemeral,
-            },
-        })
-        return
-    }
-
-    // Benutzer als spielend markieren
-    setUserPlaying(m.Member.User.ID, true)
-    defer setUserPlaying(m.Member.User.ID, false)
+	// Benutzer als spielend markieren, die Sperre gilt auch über mehrere Bot-Instanzen hinweg
+	lock, err := acquirePlayerLock(db, m.Member.User.ID, m.GuildID, m.ID)
+	if err != nil {
+		log.Printf("Fehler beim Sperren des Spielers: %v", err)
+		respondEphemeral(s, m, "Fehler beim Starten des Spiels. Bitte versuche es später erneut.")
+		return
+	}
+	if lock == nil {
+		respondEphemeral(s, m, "Du spielst bereits ein Spiel! Bitte warte, bis es beendet ist.")
+		return
+	}
+	defer lock.Release()
 
-    // Balance überprüfen
-    var balance float32
-    err := db.QueryRow("SELECT balance FROM users WHERE user_id = $1 AND guild_id = $2", m.Member.User.ID, m.GuildID).Scan(&balance)
-    if err != nil {
-        if err == sql.ErrNoRows {
-            // Benutzer existiert nicht, erstelle ihn mit Startguthaben
-            _, err = db.Exec("INSERT INTO users (user_id, guild_id, balance) VALUES ($1, $2, $3)", m.Member.User.ID, m.GuildID, 1000)
-            if err != nil {
-                s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-                    Type: discordgo.InteractionResponseChannelMessageWithSource,
-                    Data: &discordgo.InteractionResponseData{
-                        Content: "Fehler beim Erstellen des Benutzerkontos.",
-                        Flags: discordgo.MessageFlagsEphemeral,
-                    },
-                })
-                return
-            }
-            balance = 1000
-        } else {
-            s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-                Type: discordgo.InteractionResponseChannelMessageWithSource,
-                Data: &discordgo.InteractionResponseData{
-                    Content: "Fehler: Benutzer konnte nicht gefunden werden.",
-                    Flags: discordgo.MessageFlagsEphemeral,
-                },
-            })
-            return
-        }
-    }
+	if bet < 1 {
+		respondEphemeral(s, m, "Der Betrag zum Spielen muss mehr als 0 sein.")
+		return
+	}
+	if bet > MaxBet {
+		respondEphemeral(s, m, fmt.Sprintf("Der Einsatz darf höchstens %d pro Runde betragen.", MaxBet))
+		return
+	}
+	// Kann nach der Prüfung gegen MaxBet nicht überlaufen
+	totalBetCents, _ := coinsToCents(bet * autoSlotRounds)
 
-    if bet < 1 {
-        s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-            Type: discordgo.InteractionResponseChannelMessageWithSource,
-            Data: &discordgo.InteractionResponseData{
-                Content: "Der Betrag zum Spielen muss mehr als 0 sein.",
-                Flags: discordgo.MessageFlagsEphemeral,
-            },
-        })
-        return
-    }
+	// Einsatz für alle Runden vorab abbuchen, Gewinne werden pro Runde gutgeschrieben
+	currentBalance, err := debitBet(db, m.Member.User.ID, m.GuildID, totalBetCents, TxAutoSlotBet, m.ID)
+	if err == errInsufficientFunds {
+		respondEphemeral(s, m, "Nicht genug Spielgeld für 10 Spiele.")
+		return
+	}
+	if err != nil {
+		log.Printf("Fehler beim Abbuchen des Einsatzes: %v", err)
+		respondEphemeral(s, m, "Fehler beim Abbuchen des Einsatzes. Bitte versuche es später erneut.")
+		return
+	}
 
-    if m.Member.User.ID != "423480294948208661" {
-        if balance < float32(bet*10) {
-            s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-                Type: discordgo.InteractionResponseChannelMessageWithSource,
-                Data: &discordgo.InteractionResponseData{
-                    Content: "Nicht genug Spielgeld für 10 Spiele.",
-                    Flags: discordgo.MessageFlagsEphemeral,
-                },
-            })
-            return
-        }
-    }
+	respondEphemeral(s, m, fmt.Sprintf("Du spielst 10 Spiele mit je: %d", bet))
 
-    s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
-        Type: discordgo.InteractionResponseChannelMessageWithSource,
-        Data: &discordgo.InteractionResponseData{
-            Content: fmt.Sprintf("Du spielst 10 Spiele mit je: %d", bet),
-            Flags: discordgo.MessageFlagsEphemeral,
-        },
-    })
-    totalPayout := float32(0)
-    currentBalance := balance
-
-    embed := &discordgo.MessageEmbed{
-        Title:     "Auto Slot Machine",
-        Color:     0x00ccff,
-        Timestamp: time.Now().Format(time.RFC3339),
-    }
+	embed := &discordgo.MessageEmbed{
+		Title:     "Auto Slot Machine",
+		Color:     0x00ccff,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
 
-    msg, _ := s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	msg, err := s.ChannelMessageSendEmbed(m.ChannelID, embed)
+	if err != nil {
+		// Ohne Nachricht sieht der Spieler kein Ergebnis, daher den Einsatz zurückbuchen
+		log.Printf("Fehler beim Senden der Auto Slot Machine: %v", err)
+		if _, err := creditSpin(db, m.Member.User.ID, m.GuildID, totalBetCents, TxRefund, m.ID); err != nil {
+			log.Printf("Fehler beim Erstatten des Einsatzes: %v", err)
+		}
+		return
+	}
 
-    for i := 1; i <= 10; i++ {
-        // Slot-Maschine drehen
-        board := spinSlotMachine()
-        fixedBoard := convertToFixedArray(board)
-        payout, _ := calculatePayoutWithCombinations(fixedBoard, bet)
-        totalPayout += payout
+	// Ein Neuladen der Maschine wirkt sich erst auf das nächste Spiel aus
+	machine := currentMachine()
+	totalPayout := int64(0)
+	creditFailed := false
+	for i := 1; i <= autoSlotRounds; i++ {
+		// Slot-Maschine drehen
+		board := spinSlotMachine(machine)
+		fixedBoard := convertToFixedArray(board)
+		payout, _, err := calculatePayoutWithCombinations(machine, fixedBoard, bet)
+		if err != nil {
+			log.Printf("Fehler beim Berechnen des Gewinns: %v", err)
+			creditFailed = true
+		}
+		totalPayout += payout
 
-        // Guthaben aktualisieren
-        if payout > 0 {
-            currentBalance += payout - float32(bet)
-        } else {
-            currentBalance -= float32(bet)
-        }
+		// Gewinn dieser Runde gutschreiben
+		if payout > 0 {
+			balance, err := creditSpin(db, m.Member.User.ID, m.GuildID, payout, TxAutoSlotWin, fmt.Sprintf("%s/%d", m.ID, i))
+			if err != nil {
+				log.Printf("Fehler beim Gutschreiben des Gewinns: %v", err)
+				creditFailed = true
+			} else {
+				currentBalance = balance
+			}
+		}
 
-        // Embed aktualisieren
-        embed.Description = fmt.Sprintf(
-            " <@%s> Spiel %d/10\n\n%s\n\nEinsatz: %d\nGewinn: %.0f\nAktueller Kontostand: %.0f",
-        	m.Member.User.ID,
+		// Embed aktualisieren
+		embed.Description = fmt.Sprintf(
+			" <@%s> Spiel %d/10\n\n%s\n\nEinsatz: %d\nGewinn: %s\nAktueller Kontostand: %s",
+			m.Member.User.ID,
 			i,
-            formatSlotBoard(board),
-            bet,
-            payout,
-            currentBalance,
-        )
-
-        s.ChannelMessageEditEmbed(m.ChannelID, msg.ID, embed)
-        time.Sleep(1 * time.Second)
-    }
+			formatSlotBoard(board),
+			bet,
+			FormatCoins(payout),
+			FormatCoins(currentBalance),
+		)
 
-    // Gesamtergebnis anzeigen
-    finalEmbed := &discordgo.MessageEmbed{
-        Title:       "Auto Slot Machine - Ergebnis",
-        Description: fmt.Sprintf("<@%s> Nach 10 Spielen:\n\nGesamteinsatz: %d\nGesamtgewinn: %.0f\nEndkontostand: %.0f", m.Member.User.ID, bet*10, totalPayout, currentBalance),
-        Color:       0x00ccff,
-        Timestamp:   time.Now().Format(time.RFC3339),
-    }
+		s.ChannelMessageEditEmbed(m.ChannelID, msg.ID, embed)
+		time.Sleep(1 * time.Second)
+	}
 
-    s.ChannelMessageEditEmbed(m.ChannelID, msg.ID, finalEmbed)
+	// Gesamtergebnis anzeigen
+	description := fmt.Sprintf("<@%s> Nach 10 Spielen:\n\nGesamteinsatz: %d\nGesamtgewinn: %s\nEndkontostand: %s", m.Member.User.ID, bet*autoSlotRounds, FormatCoins(totalPayout), FormatCoins(currentBalance))
+	if creditFailed {
+		description += "\n\n⚠️ Nicht alle Gewinne konnten gutgeschrieben werden, bitte melde dich bei einem Admin."
+	}
+	finalEmbed := &discordgo.MessageEmbed{
+		Title:       "Auto Slot Machine - Ergebnis",
+		Description: description,
+		Color:       0x00ccff,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
 
-    // Endgültiges Guthaben in der Datenbank speichern
-    db.Exec("UPDATE users SET balance = $1 WHERE user_id = $2 AND guild_id = $3", currentBalance, m.Member.User.ID, m.GuildID)
-}
\ No newline at end of file
+	s.ChannelMessageEditEmbed(m.ChannelID, msg.ID, finalEmbed)
+}
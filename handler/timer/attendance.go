@@ -0,0 +1,425 @@
+package timer
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"discord-bot-go/handler/slots"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AttendanceButtonID ist die Custom-ID des Einchecken-Buttons auf den Vorlesungs-Embeds
+const AttendanceButtonID = "lecture_attendance"
+
+// Standardbelohnung in Müller Coins fürs Einchecken in eine Vorlesung
+const defaultAttendanceReward = 25
+
+// Maximale Anzahl an Modulen in der Statistik, damit das Embed-Feld nicht zu lang wird
+const maxStatsModules = 15
+
+type attendanceRecord struct {
+	UserID       string
+	LectureName  string
+	LectureStart time.Time
+	LectureEnd   time.Time
+	CheckedInAt  time.Time
+}
+
+func attendanceComponents() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Anwesend",
+					Style:    discordgo.SuccessButton,
+					CustomID: AttendanceButtonID,
+					Emoji:    &discordgo.ComponentEmoji{Name: "✅"},
+				},
+			},
+		},
+	}
+}
+
+// attendanceSnapshot enthält die Vorlesungen, in die gerade eingecheckt werden kann. Der
+// Button liest nur diese Kopie, denn checkAndUpdate hält t.mu auch während Discord-Aufrufen
+// und ein Klick muss innerhalb von 3 Sekunden beantwortet werden.
+type attendanceSnapshot struct {
+	lectures map[string]ActiveLectureState // nach Nachrichten-ID
+	reward   int
+}
+
+// publishAttendance aktualisiert die Kopie für den Einchecken-Button, erfordert t.mu
+func (t *lectureTracker) publishAttendance() {
+	snapshot := &attendanceSnapshot{
+		lectures: make(map[string]ActiveLectureState),
+		reward:   t.config.AttendanceReward,
+	}
+	if t.currentLecture != nil && t.currentLecture.Mode == LectureRunning {
+		snapshot.lectures[t.currentLecture.MessageID] = *t.currentLecture
+	}
+	for _, parallel := range t.parallelLectures {
+		snapshot.lectures[parallel.MessageID] = *parallel
+	}
+	t.attendance.Store(snapshot)
+}
+
+// lectureForMessage liefert die laufende Vorlesung, zu der ein Embed gehört, und die aktuelle Belohnung
+func (t *lectureTracker) lectureForMessage(messageID string) (*ActiveLectureState, int) {
+	snapshot := t.attendance.Load()
+	if snapshot == nil {
+		return nil, 0
+	}
+	state, ok := snapshot.lectures[messageID]
+	if !ok {
+		return nil, snapshot.reward
+	}
+	return &state, snapshot.reward
+}
+
+// AttendanceButton trägt die Anwesenheit ein, wenn ein Student auf einem Vorlesungs-Embed eincheckt
+func AttendanceButton(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	t := trackerForGuild(m.GuildID)
+	if t == nil || m.Member == nil || m.Message == nil {
+		respondEphemeral(s, m, "Diese Vorlesung ist nicht mehr aktiv.")
+		return
+	}
+
+	lecture, reward := t.lectureForMessage(m.Message.ID)
+
+	// Nur während der Vorlesung, damit nachträgliches Einchecken nicht möglich ist
+	now := time.Now()
+	if lecture == nil || now.Before(lecture.LectureStart) || !now.Before(lecture.LectureEnd) {
+		respondEphemeral(s, m, "Diese Vorlesung ist nicht mehr aktiv.")
+		return
+	}
+
+	// Anwesenheit und Belohnung in einer Transaktion, damit es nie das eine ohne das andere gibt
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Fehler beim Einchecken: %v", err)
+		respondEphemeral(s, m, "Fehler beim Speichern der Anwesenheit.")
+		return
+	}
+	defer tx.Rollback()
+
+	checkedIn, err := recordAttendance(tx, m.GuildID, attendanceRecord{
+		UserID:       m.Member.User.ID,
+		LectureName:  lecture.LectureName,
+		LectureStart: lecture.LectureStart,
+		LectureEnd:   lecture.LectureEnd,
+	})
+	if err != nil {
+		log.Printf("Fehler beim Einchecken: %v", err)
+		respondEphemeral(s, m, "Fehler beim Speichern der Anwesenheit.")
+		return
+	}
+	if !checkedIn {
+		respondEphemeral(s, m, fmt.Sprintf("Du bist in **%s** bereits eingecheckt.", lecture.LectureName))
+		return
+	}
+
+	if reward > 0 {
+		// Die Anwesenheit sichert ab, dass es die Belohnung nur einmal pro Vorlesung gibt
+		reference := lecture.LectureName + "@" + lecture.LectureStart.UTC().Format(time.RFC3339)
+		if err := slots.CreditTx(tx, m.Member.User.ID, m.GuildID, reward, slots.TxAttendance, reference); err != nil {
+			log.Printf("Fehler bei der Anwesenheitsbelohnung: %v", err)
+			respondEphemeral(s, m, "Fehler beim Gutschreiben der Müller Coins. Bitte versuche es erneut.")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Fehler beim Einchecken: %v", err)
+		respondEphemeral(s, m, "Fehler beim Speichern der Anwesenheit. Bitte versuche es erneut.")
+		return
+	}
+
+	if reward <= 0 {
+		respondEphemeral(s, m, fmt.Sprintf("✅ Anwesenheit in **%s** eingetragen.", lecture.LectureName))
+		return
+	}
+	respondEphemeral(s, m, fmt.Sprintf("✅ Anwesenheit in **%s** eingetragen, du erhältst **%d** Müller Coins.", lecture.LectureName, reward))
+}
+
+// StatsCommand verarbeitet /stats vorlesungen|export
+func StatsCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	switch options[0].Name {
+	case "vorlesungen":
+		lectureStats(s, m, db)
+	case "export":
+		attendanceExport(s, m, db)
+	default:
+		log.Printf("Unbekannter /stats Unterbefehl: %s", options[0].Name)
+	}
+}
+
+func lectureStats(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	t := trackerForGuild(m.GuildID)
+	if t == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	userID := m.Member.User.ID
+	now := time.Now()
+
+	// Auswertung für das aktuelle Semester, ohne Semester seit der ersten Anwesenheit auf dem Server
+	from, label, err := statsPeriod(db, m.GuildID, t.location, now)
+	if err != nil {
+		log.Printf("Fehler bei /stats vorlesungen: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden der Statistik.")
+		return
+	}
+
+	records, err := loadAttendance(db, m.GuildID, userID, from)
+	if err != nil {
+		log.Printf("Fehler bei /stats vorlesungen: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden der Statistik.")
+		return
+	}
+
+	lectures, err := t.upcomingLectures(from, now)
+	if err != nil {
+		log.Printf("Fehler bei /stats vorlesungen: %v", err)
+		respondEphemeral(s, m, "Fehler beim Abrufen des Kalenders.")
+		return
+	}
+
+	var held []LectureEvent
+	for _, lecture := range lectures {
+		if !lecture.End.After(now) {
+			held = append(held, lecture)
+		}
+	}
+
+	attended := make(map[string]bool, len(records))
+	hours := make(map[string]float64)
+	for _, record := range records {
+		attended[record.LectureName+"@"+record.LectureStart.UTC().Format(time.RFC3339)] = true
+		hours[record.LectureName] += record.LectureEnd.Sub(record.LectureStart).Hours()
+	}
+
+	attendedHeld := 0
+	for _, lecture := range held {
+		if attended[lecture.Name+"@"+lecture.Start.UTC().Format(time.RFC3339)] {
+			attendedHeld++
+		}
+	}
+
+	percentage := 0.0
+	if len(held) > 0 {
+		percentage = float64(attendedHeld) / float64(len(held)) * 100
+	}
+
+	current, longest := attendanceStreaks(held, attended, t.location)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📊 Deine Vorlesungsstatistik",
+		Description: label,
+		Color:       0x00ccff,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Anwesenheit",
+				Value:  fmt.Sprintf("%d von %d Vorlesungen (%.1f %%)\n%s", attendedHeld, len(held), percentage, createProgressBar(percentage, 20)),
+				Inline: false,
+			},
+			{
+				Name:   "Streak",
+				Value:  fmt.Sprintf("🔥 Aktuell: %d Tage\n🏆 Rekord: %d Tage", current, longest),
+				Inline: true,
+			},
+			{
+				Name:   "Stunden pro Modul",
+				Value:  formatModuleHours(hours),
+				Inline: false,
+			},
+		},
+		Timestamp: now.Format(time.RFC3339),
+	}
+
+	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// statsPeriod liefert den Beginn des Auswertungszeitraums und eine Beschreibung dafür
+func statsPeriod(db *sql.DB, guildID string, loc *time.Location, now time.Time) (time.Time, string, error) {
+	periods, err := loadStudyPeriods(db, guildID, loc)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if semester := currentPeriod(periods, periodSemester, now); semester != nil {
+		return semester.Start, fmt.Sprintf("%s (%s)", semester.Name, formatPeriodRange(*semester)), nil
+	}
+
+	var first sql.NullTime
+	err = db.QueryRow("SELECT MIN(lecture_start) FROM attendance WHERE guild_id = $1", guildID).Scan(&first)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("fehler beim Laden der Anwesenheit: %w", err)
+	}
+	if !first.Valid {
+		return now, "Noch keine Anwesenheiten erfasst.", nil
+	}
+
+	start, _ := dayBounds(first.Time.In(loc))
+	return start, "Seit " + start.Format("02.01.2006"), nil
+}
+
+// attendanceStreaks zählt aufeinanderfolgende Vorlesungstage, an denen mindestens eine Anwesenheit vorliegt
+func attendanceStreaks(held []LectureEvent, attended map[string]bool, loc *time.Location) (current, longest int) {
+	var days []string
+	attendedDays := make(map[string]bool)
+	for _, lecture := range held {
+		day := lecture.Start.In(loc).Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1] != day {
+			days = append(days, day)
+		}
+		if attended[lecture.Name+"@"+lecture.Start.UTC().Format(time.RFC3339)] {
+			attendedDays[day] = true
+		}
+	}
+
+	streak := 0
+	for _, day := range days {
+		if attendedDays[day] {
+			streak++
+			if streak > longest {
+				longest = streak
+			}
+		} else {
+			streak = 0
+		}
+	}
+	return streak, longest
+}
+
+func formatModuleHours(hours map[string]float64) string {
+	if len(hours) == 0 {
+		return "Noch keine Anwesenheiten erfasst."
+	}
+
+	modules := make([]string, 0, len(hours))
+	for module := range hours {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return hours[modules[i]] > hours[modules[j]]
+	})
+
+	var lines []string
+	for i, module := range modules {
+		if i == maxStatsModules {
+			lines = append(lines, fmt.Sprintf("... und %d weitere", len(modules)-maxStatsModules))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("**%s**: %.1f Std.", module, hours[module]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attendanceExport liefert alle Anwesenheiten der Gilde als CSV-Datei (nur für Admins)
+func attendanceExport(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+		respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um die Anwesenheit zu exportieren.")
+		return
+	}
+
+	records, err := loadAttendance(db, m.GuildID, "", time.Time{})
+	if err != nil {
+		log.Printf("Fehler bei /stats export: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden der Anwesenheit.")
+		return
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"user_id", "vorlesung", "beginn", "ende", "eingecheckt"})
+	for _, record := range records {
+		w.Write([]string{
+			record.UserID,
+			record.LectureName,
+			record.LectureStart.Format(time.RFC3339),
+			record.LectureEnd.Format(time.RFC3339),
+			record.CheckedInAt.Format(time.RFC3339),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("Fehler bei /stats export: %v", err)
+		respondEphemeral(s, m, "Fehler beim Erstellen der CSV-Datei.")
+		return
+	}
+
+	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("📄 %d Anwesenheiten exportiert.", len(records)),
+			Flags:   discordgo.MessageFlagsEphemeral,
+			Files: []*discordgo.File{{
+				Name:        "anwesenheit.csv",
+				ContentType: "text/csv",
+				Reader:      &buf,
+			}},
+		},
+	})
+}
+
+// recordAttendance trägt eine Anwesenheit ein und liefert false, wenn sie bereits existiert
+func recordAttendance(tx *sql.Tx, guildID string, record attendanceRecord) (bool, error) {
+	result, err := tx.Exec(`INSERT INTO attendance (guild_id, user_id, lecture_name, lecture_start, lecture_end)
+		VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+		guildID, record.UserID, record.LectureName, record.LectureStart, record.LectureEnd)
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Speichern der Anwesenheit: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func saveAttendanceReward(db *sql.DB, guildID string, coins int) error {
+	_, err := db.Exec("UPDATE timer_config SET attendance_reward = $1, updated_at = CURRENT_TIMESTAMP WHERE guild_id = $2", coins, guildID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Belohnung: %w", err)
+	}
+	return nil
+}
+
+// loadAttendance lädt die Anwesenheiten einer Gilde ab from, optional nur für einen Nutzer
+func loadAttendance(db *sql.DB, guildID, userID string, from time.Time) ([]attendanceRecord, error) {
+	rows, err := db.Query(`SELECT user_id, lecture_name, lecture_start, lecture_end, checked_in_at FROM attendance
+		WHERE guild_id = $1 AND ($2 = '' OR user_id = $2) AND lecture_start >= $3
+		ORDER BY lecture_start, user_id`, guildID, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Anwesenheit: %w", err)
+	}
+	defer rows.Close()
+
+	var records []attendanceRecord
+	for rows.Next() {
+		var record attendanceRecord
+		if err := rows.Scan(&record.UserID, &record.LectureName, &record.LectureStart, &record.LectureEnd, &record.CheckedInAt); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Anwesenheit: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
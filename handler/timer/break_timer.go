@@ -0,0 +1,162 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// updateBreakOrSummary zeigt nach einer Vorlesung die Pause bis zur nächsten Vorlesung
+// des Tages an oder schließt den Tag mit einer Zusammenfassung ab
+func (t *lectureTracker) updateBreakOrSummary(s *discordgo.Session) {
+	state := t.currentLecture
+
+	breakStart := state.LectureEnd
+	if state.Mode == BreakRunning {
+		breakStart = state.LectureStart
+	}
+
+	next, err := t.nextLectureOnDay(breakStart)
+	if err != nil {
+		fmt.Println("Fehler beim Ermitteln der nächsten Vorlesung:", err)
+		return
+	}
+
+	if next == nil {
+		t.showDaySummary(s, breakStart)
+		return
+	}
+
+	t.showBreak(s, breakStart, next)
+}
+
+// nextLectureOnDay liefert die nächste noch nicht begonnene Vorlesung am selben Tag wie after
+func (t *lectureTracker) nextLectureOnDay(after time.Time) (*LectureEvent, error) {
+	now := time.Now()
+	_, endOfDay := dayBounds(after.In(t.location))
+	if !endOfDay.After(now) {
+		return nil, nil
+	}
+
+	lectures, err := t.upcomingLectures(now, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lecture := range lectures {
+		if !lecture.Start.Before(after) {
+			return &lecture, nil
+		}
+	}
+	return nil, nil
+}
+
+func (t *lectureTracker) showBreak(s *discordgo.Session, breakStart time.Time, next *LectureEvent) {
+	pause := &LectureEvent{Name: next.Name, Start: breakStart, End: next.Start}
+	remaining, percentage := getLectureProgressFromEvent(pause)
+	if remaining < 0 {
+		remaining = 0
+	}
+	if percentage > 100 {
+		percentage = 100
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("☕ Pause (%s - %s)", breakStart.Format("15:04"), next.Start.Format("15:04")),
+		Description: fmt.Sprintf("Als Nächstes: **%s** um %s", next.Name, next.Start.Format("15:04")),
+		Color:       0x99cc00,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Name:   "Verbleibende Pause",
+				Value:  formatTimeFromMinutes(remaining),
+				Inline: true,
+			},
+			{
+				Name:   "Fortschritt",
+				Value:  fmt.Sprintf("%.1f %%", percentage),
+				Inline: true,
+			},
+			{
+				Name:   "Fortschrittsbalken",
+				Value:  createProgressBar(percentage, 20),
+				Inline: false,
+			},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	state := *t.currentLecture
+	t.editActiveEmbed(s, embed, withProgressCard(embed, percentage, remaining))
+	if t.currentLecture == nil {
+		return
+	}
+
+	// Nur speichern, wenn sich der Zustand geändert hat
+	if state.Mode != BreakRunning || state.LectureName != next.Name || !state.LectureEnd.Equal(next.Start) {
+		state.Mode = BreakRunning
+		state.LectureName = next.Name
+		state.LectureStart = breakStart
+		state.LectureEnd = next.Start
+		t.setCurrentLecture(&state)
+	}
+}
+
+// finishBreak schließt das Pausen-Embed ab, sobald die nächste Vorlesung beginnt
+func (t *lectureTracker) finishBreak(s *discordgo.Session, next *LectureEvent) {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("☕ Pause (%s - %s)", t.currentLecture.LectureStart.Format("15:04"), next.Start.Format("15:04")),
+		Description: fmt.Sprintf("Die Pause ist vorbei, weiter geht's mit **%s**!", next.Name),
+		Color:       0x99cc00,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	t.editActiveEmbed(s, embed, nil)
+	t.setCurrentLecture(nil)
+}
+
+// showDaySummary ersetzt das Embed nach der letzten Vorlesung durch eine Tageszusammenfassung
+func (t *lectureTracker) showDaySummary(s *discordgo.Session, day time.Time) {
+	startOfDay, endOfDay := dayBounds(day.In(t.location))
+	lectures, err := t.upcomingLectures(startOfDay, endOfDay)
+	if err != nil {
+		fmt.Println("Fehler beim Erstellen der Tageszusammenfassung:", err)
+		return
+	}
+
+	var total time.Duration
+	for _, lecture := range lectures {
+		total += lecture.End.Sub(lecture.Start)
+	}
+
+	fields := formatLectureDays(lectures)
+	fields = append(fields,
+		&discordgo.MessageEmbedField{
+			Name:   "Vorlesungen",
+			Value:  fmt.Sprintf("%d", len(lectures)),
+			Inline: true,
+		},
+		&discordgo.MessageEmbedField{
+			Name:   "Vorlesungszeit",
+			Value:  formatTimeFromMinutes(int(total.Minutes())) + " Std.",
+			Inline: true,
+		},
+	)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "🎉 Feierabend!",
+		Description: fmt.Sprintf("Alle Vorlesungen am %s, %s sind geschafft.", germanWeekdays[startOfDay.Weekday()], startOfDay.Format("02.01.2006")),
+		Color:       0x00ff00,
+		Fields:      fields,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	t.editActiveEmbed(s, embed, nil)
+	t.setCurrentLecture(nil)
+}
+
+// dayBounds liefert Beginn und Ende des Tages von t in dessen Zeitzone
+func dayBounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,270 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/arran4/golang-ical"
+	"github.com/bwmarrin/discordgo"
+)
+
+// Zeitraum, für den Änderungen am Vorlesungsplan gemeldet werden
+const changeNotificationWindow = 14 * 24 * time.Hour
+
+type changeKind int
+
+const (
+	lectureAdded changeKind = iota
+	lectureCancelled
+	lectureMoved
+	lectureRoomChanged
+)
+
+type calendarChange struct {
+	Kind changeKind
+	Old  LectureEvent
+	New  LectureEvent
+}
+
+// snapshotKey liefert den Schlüssel, unter dem eine Vorlesung im Snapshot abgelegt wird
+func snapshotKey(lecture LectureEvent) string {
+	if lecture.UID != "" {
+		return lecture.UID
+	}
+	return lecture.Name + "@" + lecture.Start.UTC().Format(time.RFC3339)
+}
+
+// diffLectures vergleicht zwei Stände des Kalenders und liefert alle Änderungen im Zeitraum [from, to)
+func diffLectures(previous, current []LectureEvent, from, to time.Time) []calendarChange {
+	inWindow := func(lecture LectureEvent) bool {
+		return !lecture.Start.Before(from) && lecture.Start.Before(to)
+	}
+
+	oldByKey := make(map[string]LectureEvent, len(previous))
+	for _, lecture := range previous {
+		oldByKey[snapshotKey(lecture)] = lecture
+	}
+
+	var changes []calendarChange
+	seen := make(map[string]bool, len(current))
+
+	for _, lecture := range current {
+		key := snapshotKey(lecture)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		old, existed := oldByKey[key]
+		if !existed {
+			if inWindow(lecture) {
+				changes = append(changes, calendarChange{Kind: lectureAdded, New: lecture})
+			}
+			continue
+		}
+
+		if !inWindow(old) && !inWindow(lecture) {
+			continue
+		}
+
+		if !old.Start.Equal(lecture.Start) || !old.End.Equal(lecture.End) {
+			changes = append(changes, calendarChange{Kind: lectureMoved, Old: old, New: lecture})
+		} else if old.Location != lecture.Location {
+			changes = append(changes, calendarChange{Kind: lectureRoomChanged, Old: old, New: lecture})
+		}
+	}
+
+	for key, old := range oldByKey {
+		if !seen[key] && inWindow(old) {
+			changes = append(changes, calendarChange{Kind: lectureCancelled, Old: old})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].sortTime().Before(changes[j].sortTime())
+	})
+
+	return changes
+}
+
+func (c calendarChange) sortTime() time.Time {
+	if c.Kind == lectureCancelled {
+		return c.Old.Start
+	}
+	return c.New.Start
+}
+
+// detectCalendarChanges vergleicht den frisch geladenen Kalender mit dem gespeicherten Snapshot
+func (t *lectureTracker) detectCalendarChanges(cal *ics.Calendar) {
+	now := time.Now()
+	current := t.snapshotLectures(cal, now)
+
+	previous, hasSnapshot, err := loadCalendarSnapshot(t.db, t.config.GuildID, t.location)
+	if err != nil {
+		fmt.Println("Fehler beim Laden des Kalender-Snapshots:", err)
+		return
+	}
+
+	// Beim ersten Abruf gibt es nichts zu vergleichen
+	if hasSnapshot {
+		changes := diffLectures(previous, current, now, now.Add(changeNotificationWindow))
+		if len(changes) > 0 {
+			t.postCalendarChanges(changes)
+		}
+	}
+
+	if err := saveCalendarSnapshot(t.db, t.config.GuildID, current); err != nil {
+		fmt.Println("Fehler beim Speichern des Kalender-Snapshots:", err)
+	}
+}
+
+// snapshotLectures liefert alle noch nicht beendeten Vorlesungen für den Snapshot
+func (t *lectureTracker) snapshotLectures(cal *ics.Calendar, now time.Time) []LectureEvent {
+	var current []LectureEvent
+	for _, lecture := range t.lecturesFromCalendar(cal) {
+		// Vergangene Termine müssen nicht im Snapshot landen
+		if lecture.End.After(now) {
+			current = append(current, lecture)
+		}
+	}
+	return current
+}
+
+func (t *lectureTracker) postCalendarChanges(changes []calendarChange) {
+	if t.session == nil {
+		return
+	}
+
+	groups := map[changeKind]*discordgo.MessageEmbedField{
+		lectureAdded:       {Name: "➕ Neue Vorlesungen"},
+		lectureCancelled:   {Name: "❌ Entfallene Vorlesungen"},
+		lectureMoved:       {Name: "🕐 Verschobene Vorlesungen"},
+		lectureRoomChanged: {Name: "🚪 Raumänderungen"},
+	}
+
+	for _, change := range changes {
+		var line string
+		switch change.Kind {
+		case lectureAdded:
+			line = fmt.Sprintf("**%s** – %s", change.New.Name, formatLectureTime(change.New))
+		case lectureCancelled:
+			line = fmt.Sprintf("~~%s~~ – %s", change.Old.Name, formatLectureTime(change.Old))
+		case lectureMoved:
+			line = fmt.Sprintf("**%s** – %s ➜ %s", change.New.Name, formatLectureTime(change.Old), formatLectureTime(change.New))
+		case lectureRoomChanged:
+			line = fmt.Sprintf("**%s** – %s: %s ➜ %s", change.New.Name, formatLectureTime(change.New), orDash(change.Old.Location), orDash(change.New.Location))
+		}
+
+		field := groups[change.Kind]
+		// Discord erlaubt maximal 1024 Zeichen pro Feld
+		if len(field.Value)+len(line)+1 > 1000 {
+			continue
+		}
+		if field.Value != "" {
+			field.Value += "\n"
+		}
+		field.Value += line
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, kind := range []changeKind{lectureAdded, lectureCancelled, lectureMoved, lectureRoomChanged} {
+		if groups[kind].Value != "" {
+			fields = append(fields, groups[kind])
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "📅 Änderungen im Vorlesungsplan",
+		Description: fmt.Sprintf("Für die nächsten %d Tage wurden %d Änderungen gefunden:", int(changeNotificationWindow.Hours()/24), len(changes)),
+		Color:       0xffa500,
+		Fields:      fields,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	if _, err := t.session.ChannelMessageSendEmbed(t.config.ChannelID, embed); err != nil {
+		fmt.Println("Fehler beim Senden der Kalenderänderungen:", err)
+	}
+}
+
+func formatLectureTime(lecture LectureEvent) string {
+	return fmt.Sprintf("%s, %s - %s",
+		germanWeekdays[lecture.Start.Weekday()],
+		lecture.Start.Format("02.01. 15:04"),
+		lecture.End.Format("15:04"))
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "–"
+	}
+	return value
+}
+
+func loadCalendarSnapshot(db *sql.DB, guildID string, loc *time.Location) ([]LectureEvent, bool, error) {
+	var hasSnapshot bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM calendar_snapshot_state WHERE guild_id = $1)", guildID).Scan(&hasSnapshot)
+	if err != nil {
+		return nil, false, fmt.Errorf("fehler beim Prüfen des Kalender-Snapshots: %w", err)
+	}
+
+	rows, err := db.Query(`SELECT event_uid, lecture_name, location, lecture_start, lecture_end
+		FROM calendar_snapshot WHERE guild_id = $1`, guildID)
+	if err != nil {
+		return nil, false, fmt.Errorf("fehler beim Laden des Kalender-Snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var lectures []LectureEvent
+	for rows.Next() {
+		var lecture LectureEvent
+		if err := rows.Scan(&lecture.UID, &lecture.Name, &lecture.Location, &lecture.Start, &lecture.End); err != nil {
+			return nil, false, fmt.Errorf("fehler beim Lesen des Kalender-Snapshots: %w", err)
+		}
+		lecture.Start = lecture.Start.In(loc)
+		lecture.End = lecture.End.In(loc)
+		lectures = append(lectures, lecture)
+	}
+
+	return lectures, hasSnapshot, rows.Err()
+}
+
+func saveCalendarSnapshot(db *sql.DB, guildID string, lectures []LectureEvent) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("fehler beim Starten der Transaktion: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM calendar_snapshot WHERE guild_id = $1", guildID); err != nil {
+		return fmt.Errorf("fehler beim Leeren des Kalender-Snapshots: %w", err)
+	}
+
+	for _, lecture := range lectures {
+		_, err := tx.Exec(`INSERT INTO calendar_snapshot (guild_id, event_uid, lecture_name, location, lecture_start, lecture_end)
+			VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (guild_id, event_uid) DO NOTHING`,
+			guildID, snapshotKey(lecture), lecture.Name, lecture.Location, lecture.Start, lecture.End)
+		if err != nil {
+			return fmt.Errorf("fehler beim Speichern des Kalender-Snapshots: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`INSERT INTO calendar_snapshot_state (guild_id) VALUES ($1)
+		ON CONFLICT (guild_id) DO UPDATE SET updated_at = CURRENT_TIMESTAMP`, guildID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern des Kalender-Snapshots: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func deleteCalendarSnapshot(db *sql.DB, guildID string) error {
+	if _, err := db.Exec("DELETE FROM calendar_snapshot WHERE guild_id = $1", guildID); err != nil {
+		return fmt.Errorf("fehler beim Löschen des Kalender-Snapshots: %w", err)
+	}
+	if _, err := db.Exec("DELETE FROM calendar_snapshot_state WHERE guild_id = $1", guildID); err != nil {
+		return fmt.Errorf("fehler beim Löschen des Kalender-Snapshots: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,384 @@
+package timer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/arran4/golang-ical"
+)
+
+const (
+	defaultRefreshInterval = 6 * time.Hour
+	defaultRequestTimeout  = 15 * time.Second
+	defaultMaxRetries      = 3
+	// Wartezeit nach einem fehlgeschlagenen Abruf, bevor es erneut versucht wird
+	failedFetchCooldown = 5 * time.Minute
+	// Größere Kalender werden abgelehnt, die Datei landet bei jedem Abruf in calendar_cache
+	maxCalendarSize = 5 << 20
+)
+
+// fetchSettings enthält die über Umgebungsvariablen einstellbaren Abrufparameter
+type fetchSettings struct {
+	RefreshInterval time.Duration
+	RequestTimeout  time.Duration
+	MaxRetries      int
+}
+
+func loadFetchSettings() fetchSettings {
+	settings := fetchSettings{
+		RefreshInterval: defaultRefreshInterval,
+		RequestTimeout:  defaultRequestTimeout,
+		MaxRetries:      defaultMaxRetries,
+	}
+
+	if value := os.Getenv("CALENDAR_REFRESH_INTERVAL"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			settings.RefreshInterval = d
+		} else {
+			fmt.Printf("Warnung: Ungültiges CALENDAR_REFRESH_INTERVAL %q, verwende %s\n", value, defaultRefreshInterval)
+		}
+	}
+
+	if value := os.Getenv("CALENDAR_TIMEOUT"); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			settings.RequestTimeout = d
+		} else {
+			fmt.Printf("Warnung: Ungültiges CALENDAR_TIMEOUT %q, verwende %s\n", value, defaultRequestTimeout)
+		}
+	}
+
+	if value := os.Getenv("CALENDAR_MAX_RETRIES"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			settings.MaxRetries = n
+		} else {
+			fmt.Printf("Warnung: Ungültiges CALENDAR_MAX_RETRIES %q, verwende %d\n", value, defaultMaxRetries)
+		}
+	}
+
+	return settings
+}
+
+// calendarFetcher lädt einen iCal-Kalender mit bedingten Anfragen, Retries und
+// einer in der Datenbank gespeicherten letzten funktionierenden Kopie
+type calendarFetcher struct {
+	url      string
+	db       *sql.DB
+	client   *http.Client
+	settings fetchSettings
+
+	// mu schützt den Zustand, der Netzwerkabruf selbst läuft ohne Sperre
+	mu           sync.Mutex
+	loaded       bool
+	calendar     *ics.Calendar
+	etag         string
+	lastModified string
+	lastFetch    time.Time
+	nextAttempt  time.Time
+}
+
+// cacheValidators sind die Header für bedingte Anfragen
+type cacheValidators struct {
+	ETag         string
+	LastModified string
+}
+
+func newCalendarFetcher(db *sql.DB, url string) *calendarFetcher {
+	settings := loadFetchSettings()
+	return &calendarFetcher{
+		url:      url,
+		db:       db,
+		client:   newCalendarClient(settings.RequestTimeout),
+		settings: settings,
+	}
+}
+
+// Cached liefert die zuletzt geladene Kopie ohne Netzwerkzugriff, nach einem Neustart aus der Datenbank
+func (f *calendarFetcher) Cached() *ics.Calendar {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.loadOnce()
+	return f.calendar
+}
+
+// loadOnce lädt nach einem Neustart einmalig die gespeicherte Kopie, erfordert f.mu
+func (f *calendarFetcher) loadOnce() {
+	if f.loaded {
+		return
+	}
+	f.loaded = true
+	if err := f.loadFromStore(); err != nil {
+		fmt.Println("Warnung: Gespeicherter Kalender konnte nicht geladen werden:", err)
+	}
+}
+
+// Refresh lädt den Kalender neu, sobald das Aktualisierungsintervall abgelaufen ist.
+// refreshed ist true, wenn neue Daten geladen wurden. Der Abruf kann mit Retries
+// rund eine Minute dauern und sollte daher nur vom Tracker selbst angestoßen werden.
+func (f *calendarFetcher) Refresh() (cal *ics.Calendar, refreshed bool, err error) {
+	f.mu.Lock()
+	f.loadOnce()
+
+	now := time.Now()
+	// Die Wartezeit nach einem Fehlschlag gilt auch, wenn noch keine Kopie vorhanden ist
+	if now.Before(f.nextAttempt) {
+		cal := f.calendar
+		next := f.nextAttempt
+		f.mu.Unlock()
+		if cal == nil {
+			return nil, false, fmt.Errorf("kalender nicht erreichbar, nächster Versuch um %s", next.Format("15:04"))
+		}
+		return cal, false, nil
+	}
+	if f.calendar != nil && now.Sub(f.lastFetch) < f.settings.RefreshInterval {
+		cal := f.calendar
+		f.mu.Unlock()
+		return cal, false, nil
+	}
+
+	// Bedingte Anfrage, nur wenn wir die passende Kopie auch wirklich haben
+	var validators cacheValidators
+	if f.calendar != nil {
+		validators = cacheValidators{ETag: f.etag, LastModified: f.lastModified}
+	}
+	f.mu.Unlock()
+
+	body, notModified, received, err := f.downloadWithRetry(validators)
+	var parsed *ics.Calendar
+	if err == nil && !notModified {
+		parsed, err = ics.ParseCalendar(bytes.NewReader(body))
+		if err != nil {
+			err = fmt.Errorf("fehler beim Parsen des Kalenders: %w", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err != nil {
+		f.nextAttempt = now.Add(failedFetchCooldown)
+		if f.calendar != nil {
+			fmt.Println("Warnung: Kalender nicht verfügbar, verwende letzte bekannte Version:", err)
+			return f.calendar, false, nil
+		}
+		return nil, false, err
+	}
+
+	if notModified {
+		f.lastFetch = now
+		f.touchStore()
+		return f.calendar, false, nil
+	}
+
+	f.calendar = parsed
+	f.etag = received.ETag
+	f.lastModified = received.LastModified
+	f.lastFetch = now
+	if err := f.saveToStore(body); err != nil {
+		fmt.Println("Warnung: Kalender konnte nicht gespeichert werden:", err)
+	}
+
+	return parsed, true, nil
+}
+
+// downloadWithRetry versucht den Abruf mit exponentiellem Backoff (1s, 2s, 4s, ...)
+func (f *calendarFetcher) downloadWithRetry(validators cacheValidators) (body []byte, notModified bool, received cacheValidators, err error) {
+	backoff := 1 * time.Second
+	for attempt := 0; attempt <= f.settings.MaxRetries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("Versuch %d: Kalender erneut abrufen in %s...\n", attempt+1, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		body, notModified, received, err = f.download(validators)
+		if err == nil {
+			return body, notModified, received, nil
+		}
+		fmt.Println("Fehler beim Abrufen des Kalenders:", err)
+	}
+	return nil, false, cacheValidators{}, err
+}
+
+func (f *calendarFetcher) download(validators cacheValidators) ([]byte, bool, cacheValidators, error) {
+	fmt.Println("📅 Lade Kalender von", f.url)
+	req, err := http.NewRequest(http.MethodGet, f.url, nil)
+	if err != nil {
+		return nil, false, cacheValidators{}, fmt.Errorf("fehler beim Erstellen der Anfrage: %w", err)
+	}
+
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, false, cacheValidators{}, fmt.Errorf("fehler beim Abrufen des Kalenders: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, validators, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, cacheValidators{}, fmt.Errorf("fehler beim Abrufen des Kalenders: HTTP %d", resp.StatusCode)
+	}
+
+	// Ein Byte mehr lesen, um eine zu große Datei von einer genau passenden zu unterscheiden
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarSize+1))
+	if err != nil {
+		return nil, false, cacheValidators{}, fmt.Errorf("fehler beim Lesen des Kalenders: %w", err)
+	}
+	if len(body) > maxCalendarSize {
+		return nil, false, cacheValidators{}, fmt.Errorf("kalender ist größer als %d MB", maxCalendarSize>>20)
+	}
+
+	received := cacheValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	return body, false, received, nil
+}
+
+func (f *calendarFetcher) loadFromStore() error {
+	var body []byte
+	var fetchedAt time.Time
+	err := f.db.QueryRow("SELECT body, etag, last_modified, fetched_at FROM calendar_cache WHERE ical_url = $1", f.url).
+		Scan(&body, &f.etag, &f.lastModified, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fehler beim Laden des gespeicherten Kalenders: %w", err)
+	}
+
+	cal, err := ics.ParseCalendar(bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("fehler beim Parsen des gespeicherten Kalenders: %w", err)
+	}
+
+	fmt.Printf("📅 Gespeicherten Kalender für %s vom %s geladen\n", f.url, fetchedAt.Format("02.01.2006 15:04"))
+	f.calendar = cal
+	f.lastFetch = fetchedAt
+	return nil
+}
+
+func (f *calendarFetcher) saveToStore(body []byte) error {
+	_, err := f.db.Exec(`INSERT INTO calendar_cache (ical_url, body, etag, last_modified, fetched_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (ical_url) DO UPDATE
+		SET body = EXCLUDED.body, etag = EXCLUDED.etag, last_modified = EXCLUDED.last_modified, fetched_at = EXCLUDED.fetched_at`,
+		f.url, body, f.etag, f.lastModified, f.lastFetch)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern des Kalenders: %w", err)
+	}
+	return nil
+}
+
+func (f *calendarFetcher) touchStore() {
+	_, err := f.db.Exec("UPDATE calendar_cache SET fetched_at = $1 WHERE ical_url = $2", f.lastFetch, f.url)
+	if err != nil {
+		fmt.Println("Warnung: Zeitstempel des gespeicherten Kalenders konnte nicht aktualisiert werden:", err)
+	}
+}
+
+// downloadCalendar lädt einen Kalender einmalig ohne Cache, z.B. zur Prüfung einer neuen URL
+func downloadCalendar(icalURL string) (*ics.Calendar, error) {
+	f := &calendarFetcher{
+		url:    icalURL,
+		client: newCalendarClient(loadFetchSettings().RequestTimeout),
+	}
+
+	body, _, _, err := f.download(cacheValidators{})
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := ics.ParseCalendar(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Parsen des Kalenders: %w", err)
+	}
+	return cal, nil
+}
+
+var errBlockedAddress = errors.New("adresse nicht erlaubt")
+
+// validateCalendarURL prüft eine von Admins angegebene Kalender-URL vor dem ersten Abruf
+func validateCalendarURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("ungültige URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("nur http und https sind erlaubt")
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("die URL enthält keinen Host")
+	}
+	return nil
+}
+
+// newCalendarClient erstellt einen HTTP-Client, der nur öffentliche Adressen erreicht.
+// Die Kalender-URL stammt von Server-Admins, ohne diese Prüfung ließen sich darüber
+// interne Dienste des Bot-Servers abfragen. Geprüft wird beim Verbindungsaufbau, damit
+// auch Weiterleitungen und DNS-Namen, die auf interne Adressen zeigen, abgefangen werden.
+func newCalendarClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("zu viele Weiterleitungen")
+			}
+			return validateCalendarURL(req.URL.String())
+		},
+	}
+}
+
+// isPublicIP schließt Loopback, private, Link-Local und sonstige nicht öffentliche Adressen aus
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	// Carrier-Grade-NAT (100.64.0.0/10) ist ebenfalls nicht öffentlich
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return false
+	}
+	return true
+}
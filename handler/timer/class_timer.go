@@ -1,36 +1,54 @@
 package timer
 
 import (
-	"bytes"
+	"database/sql"
 	"fmt"
-	"io"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/arran4/golang-ical"
 	"github.com/bwmarrin/discordgo"
 )
 
-const lectureChannelID = "1236999352329965608"
-const icalURL = "https://stuv.app/MGH-TINF23/ical"
-const maxLectureDuration = 4 * time.Hour
 
 type LectureSlot string
 
 const (
 	Morning   LectureSlot = "morning"
 	Afternoon LectureSlot = "afternoon"
+	Evening   LectureSlot = "evening"
 	None      LectureSlot = "none"
 )
 
+// LectureMode beschreibt, was das aktive Embed gerade anzeigt
+type LectureMode string
+
+const (
+	LectureRunning LectureMode = "lecture"
+	BreakRunning   LectureMode = "break"
+)
+
 type LectureEvent struct {
-	Name  string
-	Start time.Time
-	End   time.Time
+	UID        string
+	Name       string
+	Location   string
+	Room       string
+	Lecturer   string
+	Online     bool
+	MeetingURL string
+	Categories []string
+	Start      time.Time
+	End        time.Time
 }
 
 type ActiveLectureState struct {
+	ChannelID    string
 	MessageID    string
+	Mode         LectureMode
 	LectureSlot  LectureSlot
 	Date         string
 	LectureName  string
@@ -38,91 +56,202 @@ type ActiveLectureState struct {
 	LectureEnd   time.Time
 }
 
-var currentLecture *ActiveLectureState
-var cachedCalendar *ics.Calendar
-var lastCalendarFetch time.Time
+// lectureTracker verfolgt die Vorlesungen einer einzelnen Gilde
+type lectureTracker struct {
+	config         GuildConfig
+	location       *time.Location
+	session        *discordgo.Session
+	db             *sql.DB
+	mu             sync.Mutex
+	stop           chan struct{}
+	done           chan struct{} // wird geschlossen, sobald run beendet ist
+	currentLecture *ActiveLectureState
+	fetcher        *calendarFetcher
+
+	// filterMu schützt filter, damit Befehle den Kalender ohne t.mu auswerten können
+	filterMu sync.RWMutex
+	filter   lectureFilter
+
+	// Embeds weiterer gleichzeitig laufender Vorlesungen, nach lectureKey
+	parallelLectures map[string]*ActiveLectureState
+
+	// Kopie für den Einchecken-Button, siehe publishAttendance
+	attendance atomic.Pointer[attendanceSnapshot]
+
+	// expandedMu schützt expanded, die aufgelösten Termine des zuletzt ausgewerteten Kalenders
+	expandedMu sync.Mutex
+	expanded   expandedCalendar
+}
 
-func fetchCalendar() (*ics.Calendar, error) {
-	// Cache für 1 Woche
-	if cachedCalendar != nil && time.Since(lastCalendarFetch) < 7*24*time.Hour {
-		return cachedCalendar, nil
-	}
+// Wie lange aufgelöste Termine wiederverwendet werden, wenn sich der Kalender nicht ändert.
+// Danach wird das Fenster um die aktuelle Zeit neu berechnet.
+const expansionMaxAge = time.Hour
 
-	fmt.Println("📅 Lade Kalender von", icalURL)
-	resp, err := http.Get(icalURL)
-	if err != nil {
-		return nil, fmt.Errorf("fehler beim Abrufen des Kalenders: %w", err)
+// expandedCalendar sind die aufgelösten Termine eines Kalenders, damit RRULEs nicht bei jedem
+// Minutentakt und jedem Befehl neu über ±recurrenceHorizon aufgelöst werden
+type expandedCalendar struct {
+	cal         *ics.Calendar
+	expandedAt  time.Time
+	occurrences []occurrence
+}
+
+var (
+	trackers   = make(map[string]*lectureTracker)
+	trackersMu sync.Mutex
+
+	// startMu reiht startTracker-Aufrufe hintereinander, trackersMu wird dabei nur kurz gehalten
+	startMu sync.Mutex
+)
+
+func newLectureTracker(s *discordgo.Session, db *sql.DB, cfg GuildConfig) *lectureTracker {
+	return &lectureTracker{
+		config:   cfg,
+		location: cfg.Location(),
+		session:  s,
+		db:       db,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		fetcher:  newCalendarFetcher(db, cfg.ICalURL),
+		filter:   defaultLectureFilter(),
+
+		parallelLectures: make(map[string]*ActiveLectureState),
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("fehler beim Lesen des Kalenders: %w", err)
+// cachedCalendar liefert den zuletzt geladenen Kalender ohne Netzwerkzugriff
+func (t *lectureTracker) cachedCalendar() (*ics.Calendar, error) {
+	cal := t.fetcher.Cached()
+	if cal == nil {
+		return nil, fmt.Errorf("kalender für Gilde %s wurde noch nicht geladen", t.config.GuildID)
 	}
+	return cal, nil
+}
 
-	cal, err := ics.ParseCalendar(bytes.NewReader(body))
+// refreshCalendar lädt den Kalender bei Bedarf neu. Nur der Tracker selbst ruft das auf, und zwar
+// ohne t.mu, damit Befehle und Buttons während eines langsamen Abrufs nicht blockieren.
+func (t *lectureTracker) refreshCalendar() {
+	cal, refreshed, err := t.fetcher.Refresh()
 	if err != nil {
-		return nil, fmt.Errorf("fehler beim Parsen des Kalenders: %w", err)
+		fmt.Println("Fehler beim Abrufen des Kalenders:", err)
+		return
+	}
+	if !refreshed {
+		return
 	}
 
-	cachedCalendar = cal
-	lastCalendarFetch = time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
 
 	// Zeige die nächsten 7 Tage an Vorlesungen
-	printUpcomingLectures(cal, 7)
+	t.printUpcomingLectures(cal, 7)
 
-	return cal, nil
+	// Änderungen gegenüber dem letzten Stand melden
+	t.detectCalendarChanges(cal)
+
+	// Klausurtermine für den Countdown übernehmen
+	t.detectExams(cal)
 }
 
-func convertToLocalTime(t time.Time) time.Time {
-	// Zeitzone auf Europe/Berlin setzen
-	loc, err := time.LoadLocation("Europe/Berlin")
-	if err != nil {
-		fmt.Println("Warnung: Konnte Zeitzone nicht laden:", err)
-		return t
-	}
-	return t.In(loc)
+func (t *lectureTracker) convertToLocalTime(tm time.Time) time.Time {
+	// In die konfigurierte Zeitzone der Gilde umrechnen
+	return tm.In(t.location)
 }
 
-func printUpcomingLectures(cal *ics.Calendar, days int) {
-	fmt.Println("\n📚 Vorlesungen der nächsten", days, "Tage:")
-	fmt.Println("=====================================")
+// calendarOccurrences liefert die aufgelösten Termine des Kalenders und berechnet sie nur
+// nach einer Aktualisierung des Kalenders oder nach expansionMaxAge neu. Die zurückgegebenen
+// Termine werden geteilt und dürfen nicht verändert werden.
+func (t *lectureTracker) calendarOccurrences(cal *ics.Calendar) []occurrence {
+	t.expandedMu.Lock()
+	defer t.expandedMu.Unlock()
 
 	now := time.Now()
-	endDate := now.AddDate(0, 0, days)
+	if t.expanded.cal != cal || now.Sub(t.expanded.expandedAt) > expansionMaxAge {
+		// Statistiken brauchen vergangene Vorlesungen bis zum Semesterbeginn
+		t.expanded = expandedCalendar{
+			cal:         cal,
+			expandedAt:  now,
+			occurrences: expandCalendar(cal, now.Add(-recurrenceHorizon), now.Add(recurrenceHorizon)),
+		}
+	}
+	return t.expanded.occurrences
+}
+
+// lecturesFromCalendar liefert alle Vorlesungen des Kalenders sortiert nach Beginn,
+// wiederkehrende Termine werden dabei in einzelne Vorlesungen aufgelöst
+func (t *lectureTracker) lecturesFromCalendar(cal *ics.Calendar) []LectureEvent {
+	t.filterMu.RLock()
+	filter := t.filter
+	t.filterMu.RUnlock()
 
 	var lectures []LectureEvent
 
-	for _, event := range cal.Events() {
-		start, err := event.GetStartAt()
-		if err != nil {
+	for _, occ := range t.calendarOccurrences(cal) {
+		// In lokale Zeitzone konvertieren
+		start := t.convertToLocalTime(occ.Start)
+		end := t.convertToLocalTime(occ.End)
+
+		// Abgesagte Termine ignorieren
+		if strings.EqualFold(propertyValue(occ.Event, ics.ComponentPropertyStatus), "CANCELLED") {
 			continue
 		}
 
-		end, err := event.GetEndAt()
-		if err != nil {
+		lecture := newLectureEvent(occ)
+		lecture.Start = start
+		lecture.End = end
+
+		// Filterregeln der Gilde anwenden (Dauer, Titel, Kategorie, Ort)
+		if !filter.Allows(lecture) {
 			continue
 		}
 
-		// In lokale Zeitzone konvertieren
-		start = convertToLocalTime(start)
-		end = convertToLocalTime(end)
+		lectures = append(lectures, lecture)
+	}
 
-		duration := end.Sub(start)
+	sort.Slice(lectures, func(i, j int) bool {
+		return lectures[i].Start.Before(lectures[j].Start)
+	})
 
-		// Nur Events unter 4 Stunden (Vorlesungen)
-		if duration >= maxLectureDuration {
-			continue
+	return lectures
+}
+
+// upcomingLectures liefert alle Vorlesungen, die im Zeitraum [from, to) beginnen.
+// Es wird nur der zwischengespeicherte Kalender gelesen, t.mu ist dafür nicht nötig.
+func (t *lectureTracker) upcomingLectures(from, to time.Time) ([]LectureEvent, error) {
+	cal, err := t.cachedCalendar()
+	if err != nil {
+		return nil, err
+	}
+
+	var lectures []LectureEvent
+	for _, lecture := range t.lecturesFromCalendar(cal) {
+		if !lecture.Start.Before(from) && lecture.Start.Before(to) {
+			lectures = append(lectures, lecture)
 		}
+	}
+	return lectures, nil
+}
 
+func propertyValue(event *ics.VEvent, property ics.ComponentProperty) string {
+	prop := event.GetProperty(property)
+	if prop == nil {
+		return ""
+	}
+	return prop.Value
+}
+
+func (t *lectureTracker) printUpcomingLectures(cal *ics.Calendar, days int) {
+	fmt.Println("\n📚 Vorlesungen der nächsten", days, "Tage für Gilde", t.config.GuildID+":")
+	fmt.Println("=====================================")
+
+	now := time.Now()
+	endDate := now.AddDate(0, 0, days)
+
+	var lectures []LectureEvent
+
+	for _, lecture := range t.lecturesFromCalendar(cal) {
 		// Nur Events in den nächsten X Tagen
-		if start.After(now) && start.Before(endDate) {
-			name := event.GetProperty(ics.ComponentPropertySummary).Value
-			lectures = append(lectures, LectureEvent{
-				Name:  name,
-				Start: start,
-				End:   end,
-			})
+		if lecture.Start.After(now) && lecture.Start.Before(endDate) {
+			lectures = append(lectures, lecture)
 		}
 	}
 
@@ -143,76 +272,6 @@ func printUpcomingLectures(cal *ics.Calendar, days int) {
 	fmt.Println("=====================================")
 }
 
-func getCurrentLectureFromCalendar() *LectureEvent {
-	cal, err := fetchCalendar()
-	if err != nil {
-		fmt.Println("Fehler beim Abrufen des Kalenders:", err)
-		return nil
-	}
-
-	now := time.Now()
-
-	for _, event := range cal.Events() {
-		start, err := event.GetStartAt()
-		if err != nil {
-			continue
-		}
-
-		end, err := event.GetEndAt()
-		if err != nil {
-			continue
-		}
-
-		// In lokale Zeitzone konvertieren
-		start = convertToLocalTime(start)
-		end = convertToLocalTime(end)
-
-		duration := end.Sub(start)
-
-		// Nur Events unter 4 Stunden (Vorlesungen)
-		if duration >= maxLectureDuration {
-			continue
-		}
-
-		// Prüfen ob das Event gerade läuft
-		if now.After(start) && now.Before(end) {
-			name := event.GetProperty(ics.ComponentPropertySummary).Value
-			return &LectureEvent{
-				Name:  name,
-				Start: start,
-				End:   end,
-			}
-		}
-	}
-
-	return nil
-}
-
-func getCurrentLectureSlot() LectureSlot {
-	now := time.Now()
-	day := now.Weekday()
-
-	// Wochenende ausschließen
-	if day == time.Saturday || day == time.Sunday {
-		return None
-	}
-
-	hour := now.Hour()
-	minute := now.Minute()
-	totalMinutes := hour*60 + minute
-
-	// 9:00 - 12:15
-	if totalMinutes >= 540 && totalMinutes < 735 {
-		return Morning
-	}
-	// 13:00 - 16:15
-	if totalMinutes >= 780 && totalMinutes < 975 {
-		return Afternoon
-	}
-
-	return None
-}
-
 func getLectureProgressFromEvent(lecture *LectureEvent) (remaining int, percentage float64) {
 	now := time.Now()
 
@@ -246,26 +305,50 @@ func repeatString(char string, count int) string {
 	return result
 }
 
-func createOrUpdateLectureEmbed(s *discordgo.Session, lecture *LectureEvent) {
-	// Channel abrufen
-	channel, err := s.Channel(lectureChannelID)
-	if err != nil {
-		fmt.Printf("Channel %s nicht gefunden: %v\n", lectureChannelID, err)
-		return
+func (t *lectureTracker) createOrUpdateLectureEmbed(s *discordgo.Session, lecture *LectureEvent) {
+	view := t.buildLectureEmbed(lecture)
+
+	if t.currentLecture == nil {
+		// Neue Nachricht erstellen
+		state := t.sendLectureEmbed(s, lecture, view)
+		if state == nil {
+			return
+		}
+		t.setCurrentLecture(state)
+	} else {
+		// Nachricht aktualisieren
+		t.editActiveEmbed(s, view.Embed, view.Files, view.Components...)
 	}
 
+	// Der Zustand bleibt auch nach dem Ende erhalten, damit updateBreakOrSummary
+	// daraus die Pause bzw. den Tagesabschluss machen kann
+}
+
+// lectureView ist ein fertig aufgebautes Vorlesungs-Embed inklusive Fortschrittskarte
+type lectureView struct {
+	Embed      *discordgo.MessageEmbed
+	Files      []*discordgo.File
+	Components []discordgo.MessageComponent
+	Block      *lectureBlock
+	Finished   bool
+}
+
+// buildLectureEmbed erstellt das Fortschritts-Embed einer Vorlesung
+func (t *lectureTracker) buildLectureEmbed(lecture *LectureEvent) lectureView {
 	remaining, percentage := getLectureProgressFromEvent(lecture)
 
 	// Titel mit Vorlesungsname und Zeitraum
 	timeRange := fmt.Sprintf("%s - %s",
-			lecture.Start.Format("15:04"),
-			lecture.End.Format("15:04"))
+		lecture.Start.Format("15:04"),
+		lecture.End.Format("15:04"))
 
 	title := lecture.Name + " (" + timeRange + ")"
-	
 
+	// Nicht remaining prüfen: die Minuten sind abgeschnitten, in der letzten Minute wäre die
+	// Vorlesung sonst schon vorbei
+	finished := !time.Now().Before(lecture.End)
 	description := "Die Vorlesung läuft noch... Durchhalten!"
-	if remaining <= 0 {
+	if finished {
 		description = "Geschafft! 🎉"
 		remaining = 0
 		percentage = 100
@@ -288,6 +371,10 @@ func createOrUpdateLectureEmbed(s *discordgo.Session, lecture *LectureEvent) {
 			Inline: false,
 		},
 	}
+	fields = append(fields, lectureDetailFields(*lecture)...)
+
+	block := t.blockForLecture(lecture)
+	fields = append(fields, blockFields(block, lecture)...)
 
 	embed := &discordgo.MessageEmbed{
 		Title:       title,
@@ -296,73 +383,252 @@ func createOrUpdateLectureEmbed(s *discordgo.Session, lecture *LectureEvent) {
 		Fields:      fields,
 		Timestamp:   time.Now().Format(time.RFC3339),
 	}
+	view := lectureView{
+		Embed:    embed,
+		Files:    withProgressCard(embed, percentage, remaining),
+		Block:    block,
+		Finished: finished,
+	}
 
-	if currentLecture == nil {
-		// Neue Nachricht erstellen
-		msg, err := s.ChannelMessageSendEmbed(channel.ID, embed)
-		if err != nil {
-			fmt.Println("Fehler beim Senden der Nachricht:", err)
-			return
-		}
+	// Einchecken ist nur möglich, solange die Vorlesung läuft
+	if !finished {
+		view.Components = attendanceComponents()
+	}
+	return view
+}
 
-		currentLecture = &ActiveLectureState{
-			MessageID:    msg.ID,
-			LectureSlot:  getCurrentLectureSlot(),
-			Date:         time.Now().Format("2006-01-02"),
-			LectureName:  lecture.Name,
-			LectureStart: lecture.Start,
-			LectureEnd:   lecture.End,
-		}
+// sendLectureEmbed sendet ein neues Vorlesungs-Embed und erwähnt dabei die Teilnehmer des passenden Wahlfachs
+func (t *lectureTracker) sendLectureEmbed(s *discordgo.Session, lecture *LectureEvent, view lectureView) *ActiveLectureState {
+	// Channel abrufen
+	channel, err := s.Channel(t.config.ChannelID)
+	if err != nil {
+		fmt.Printf("Channel %s nicht gefunden: %v\n", t.config.ChannelID, err)
+		return nil
+	}
+
+	slot := None
+	if view.Block != nil {
+		slot = view.Block.Slot
+	}
+
+	msg, err := s.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+		Content:    t.trackMentions(*lecture),
+		Embeds:     []*discordgo.MessageEmbed{view.Embed},
+		Files:      view.Files,
+		Components: view.Components,
+	})
+	if err != nil {
+		fmt.Println("Fehler beim Senden der Nachricht:", err)
+		return nil
+	}
+
+	return &ActiveLectureState{
+		ChannelID:    channel.ID,
+		MessageID:    msg.ID,
+		Mode:         LectureRunning,
+		LectureSlot:  slot,
+		Date:         time.Now().In(t.location).Format("2006-01-02"),
+		LectureName:  lecture.Name,
+		LectureStart: lecture.Start,
+		LectureEnd:   lecture.End,
+	}
+}
+
+// setCurrentLecture setzt den aktiven Vorlesungszustand und speichert ihn in der Datenbank
+func (t *lectureTracker) setCurrentLecture(state *ActiveLectureState) {
+	t.currentLecture = state
+	t.publishAttendance()
+
+	var err error
+	if state == nil {
+		err = deleteLectureState(t.db, t.config.GuildID, primaryLectureKey)
 	} else {
-		// Nachricht aktualisieren
-		_, err := s.ChannelMessageEditEmbed(channel.ID, currentLecture.MessageID, embed)
-		if err != nil {
-			fmt.Println("Fehler beim Bearbeiten der Nachricht:", err)
+		err = saveLectureState(t.db, t.config.GuildID, primaryLectureKey, state)
+	}
+	if err != nil {
+		fmt.Println("Fehler beim Speichern des Vorlesungszustands:", err)
+	}
+}
+
+// editActiveEmbed aktualisiert die Nachricht des aktiven Zustands
+func (t *lectureTracker) editActiveEmbed(s *discordgo.Session, embed *discordgo.MessageEmbed, files []*discordgo.File, components ...discordgo.MessageComponent) {
+	err := editEmbedMessage(s, t.currentLecture.ChannelID, t.currentLecture.MessageID, embed, files, components...)
+	if err != nil {
+		fmt.Println("Fehler beim Bearbeiten der Nachricht:", err)
+		// Nachricht wurde gelöscht, beim nächsten Durchlauf neu senden
+		if isUnknownMessage(err) {
+			t.setCurrentLecture(nil)
 		}
 	}
+}
 
-	// Wenn die Vorlesung vorbei ist, currentLecture zurücksetzen
-	if remaining <= 0 {
-		currentLecture = nil
+// finishCurrentLecture schließt das Embed der gespeicherten Vorlesung ab
+func (t *lectureTracker) finishCurrentLecture(s *discordgo.Session) {
+	if t.currentLecture == nil || t.currentLecture.Mode != LectureRunning {
+		return
 	}
+
+	t.createOrUpdateLectureEmbed(s, &LectureEvent{
+		Name:  t.currentLecture.LectureName,
+		Start: t.currentLecture.LectureStart,
+		End:   t.currentLecture.LectureEnd,
+	})
+	t.setCurrentLecture(nil)
 }
 
-func CheckAndUpdateLecture(s *discordgo.Session) {
-	lecture := getCurrentLectureFromCalendar()
+func isUnknownMessage(err error) bool {
+	restErr, ok := err.(*discordgo.RESTError)
+	return ok && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound
+}
+
+func (t *lectureTracker) checkAndUpdate(s *discordgo.Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Erinnerungen für bald beginnende Vorlesungen verschicken
+	t.sendReminders(s)
+
+	running, err := t.runningLectures(time.Now())
+	if err != nil {
+		fmt.Println("Fehler beim Abrufen des Kalenders:", err)
+		return
+	}
+
+	// Laufen mehrere Vorlesungen gleichzeitig, bekommt jede ein eigenes Embed
+	lecture := pickCurrentLecture(running, t.currentLecture)
+	t.promoteParallelLecture(s, lecture)
+	t.updateParallelLectures(s, running, lecture)
 
 	if lecture == nil {
-		if currentLecture != nil {
-			currentLecture = nil
+		// Vorlesung ist vorbei (auch nach einem Neustart): Pause oder Tagesabschluss anzeigen
+		if t.currentLecture != nil {
+			t.updateBreakOrSummary(s)
 		}
 		return
 	}
 
+	// Die Pause ist vorbei, die nächste Vorlesung bekommt ein eigenes Embed
+	if t.currentLecture != nil && t.currentLecture.Mode == BreakRunning {
+		t.finishBreak(s, lecture)
+	}
+
 	// Neue Vorlesung oder keine aktive Vorlesung
-	if currentLecture == nil || currentLecture.LectureName != lecture.Name || !currentLecture.LectureStart.Equal(lecture.Start) {
-		currentLecture = nil
-		createOrUpdateLectureEmbed(s, lecture)
+	if t.currentLecture == nil || t.currentLecture.LectureName != lecture.Name || !t.currentLecture.LectureStart.Equal(lecture.Start) {
+		t.finishCurrentLecture(s)
+		t.createOrUpdateLectureEmbed(s, lecture)
 	} else {
-		createOrUpdateLectureEmbed(s, lecture)
+		t.createOrUpdateLectureEmbed(s, lecture)
 	}
 }
 
-func StartLectureTimer(s *discordgo.Session) {
-	fmt.Println("Starte LectureTimer-Intervall (jede Minute)")
+func (t *lectureTracker) run(s *discordgo.Session) {
+	defer close(t.done)
 
-	// Kalender beim Start einmal laden
-	_, _ = fetchCalendar()
+	// Filterregeln, gespeicherten Zustand und Kalender beim Start einmal laden
+	t.reloadFilter()
+
+	t.mu.Lock()
+	states, err := loadLectureStates(t.db, t.config.GuildID)
+	if err != nil {
+		fmt.Println("Fehler beim Laden des Vorlesungszustands:", err)
+	}
+	t.currentLecture = states[primaryLectureKey]
+	delete(states, primaryLectureKey)
+	t.parallelLectures = states
+	t.publishAttendance()
+	t.mu.Unlock()
+
+	t.refreshCalendar()
+
+	// Direkt prüfen, damit ein bestehendes Embed nach dem Neustart sofort weiterläuft
+	t.checkAndUpdate(s)
 
 	ticker := time.NewTicker(1 * time.Minute)
-	go func() {
-		for range ticker.C {
-			CheckAndUpdateLecture(s)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.refreshCalendar()
+			t.checkAndUpdate(s)
+		case <-t.stop:
+			return
 		}
-	}()
+	}
+}
+
+// CheckAndUpdateLecture aktualisiert die Vorlesungs-Embeds aller konfigurierten Gilden
+func CheckAndUpdateLecture(s *discordgo.Session) {
+	trackersMu.Lock()
+	active := make([]*lectureTracker, 0, len(trackers))
+	for _, t := range trackers {
+		active = append(active, t)
+	}
+	trackersMu.Unlock()
+
+	for _, t := range active {
+		t.checkAndUpdate(s)
+	}
+}
+
+// startTracker startet (oder ersetzt) den Vorlesungs-Tracker einer Gilde
+func startTracker(s *discordgo.Session, db *sql.DB, cfg GuildConfig) {
+	startMu.Lock()
+	defer startMu.Unlock()
+
+	trackersMu.Lock()
+	old, exists := trackers[cfg.GuildID]
+	if exists {
+		delete(trackers, cfg.GuildID)
+		close(old.stop)
+	}
+	trackersMu.Unlock()
+
+	// Warten, bis der alte Tracker fertig ist, damit nicht zwei Tracker gleichzeitig
+	// dieselben Embeds und Zustände bearbeiten. Das kann während eines Kalenderabrufs
+	// dauern, daher ohne trackersMu, sonst hängen die Befehle aller Gilden.
+	if exists {
+		<-old.done
+	}
+
+	t := newLectureTracker(s, db, cfg)
+	trackersMu.Lock()
+	trackers[cfg.GuildID] = t
+	trackersMu.Unlock()
+	go t.run(s)
+}
+
+// trackerForGuild liefert den Vorlesungs-Tracker einer Gilde oder nil
+func trackerForGuild(guildID string) *lectureTracker {
+	trackersMu.Lock()
+	defer trackersMu.Unlock()
+	return trackers[guildID]
+}
+
+func StartLectureTimer(s *discordgo.Session, db *sql.DB) {
+	fmt.Println("Starte LectureTimer-Intervall (jede Minute)")
+
+	configs, err := loadGuildConfigs(db)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Timer-Konfigurationen:", err)
+		return
+	}
+
+	if len(configs) == 0 {
+		fmt.Println("Keine Timer-Konfiguration gefunden. Richte den Timer mit /timer setup ein.")
+	}
+
+	cleanupReminderLog(db)
+
+	for _, cfg := range configs {
+		fmt.Printf("Starte Vorlesungs-Tracker für Gilde %s (%s)\n", cfg.GuildID, cfg.ICalURL)
+		startTracker(s, db, cfg)
+	}
 }
 
 // TestCalendarDownload ist eine öffentliche Funktion zum Testen des Kalender-Downloads
-func TestCalendarDownload() {
-	cal, err := fetchCalendar()
+func TestCalendarDownload(icalURL string) {
+	cal, err := downloadCalendar(icalURL)
 	if err != nil {
 		fmt.Println("❌ Fehler:", err)
 		return
@@ -0,0 +1,318 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// TimerCommand verarbeitet die /timer Befehle
+func TimerCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+		respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um den Timer zu konfigurieren.")
+		return
+	}
+
+	switch options[0].Name {
+	case "setup":
+		timerSetup(s, m, db, options[0].Options)
+	case "status":
+		timerStatus(s, m, db)
+	case "bloecke":
+		timerBlocks(s, m, db, options[0].Options)
+	case "belohnung":
+		timerReward(s, m, db, options[0].Options)
+	case "filter":
+		if len(options[0].Options) > 0 {
+			timerFilter(s, m, db, options[0].Options[0])
+		}
+	default:
+		log.Printf("Unbekannter /timer Unterbefehl: %s", options[0].Name)
+	}
+}
+
+func timerSetup(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	cfg := GuildConfig{
+		GuildID:  m.GuildID,
+		Timezone: defaultTimezone,
+		BlockGap: defaultBlockGapMinutes * time.Minute,
+
+		AttendanceReward: defaultAttendanceReward,
+	}
+
+	for _, opt := range options {
+		switch opt.Name {
+		case "kalender":
+			cfg.ICalURL = opt.StringValue()
+		case "kanal":
+			cfg.ChannelID = opt.ChannelValue(nil).ID
+		case "zeitzone":
+			cfg.Timezone = opt.StringValue()
+		case "fortschrittskanal":
+			cfg.ProgressChannelID = opt.ChannelValue(nil).ID
+		}
+	}
+
+	if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+		respondEphemeral(s, m, fmt.Sprintf("Unbekannte Zeitzone: %s", cfg.Timezone))
+		return
+	}
+	if err := validateCalendarURL(cfg.ICalURL); err != nil {
+		respondEphemeral(s, m, "Die Kalender-URL muss eine öffentliche http- oder https-Adresse sein.")
+		return
+	}
+
+	// Der Download kann länger als 3 Sekunden dauern, daher Antwort zurückstellen
+	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+
+	cal, err := downloadCalendar(cfg.ICalURL)
+	if err != nil {
+		// Keine Details zurückgeben, die Fehlermeldung verrät sonst etwas über das interne Netz
+		log.Printf("Fehler bei /timer setup: %v", err)
+		editResponse(s, m, "Der Kalender konnte nicht geladen werden. Bitte prüfe, ob die URL öffentlich erreichbar ist und einen iCal-Kalender liefert.")
+		return
+	}
+
+	// Bei einem neuen Kalender wäre der alte Snapshot eine Flut an "Änderungen"
+	previous, err := loadGuildConfig(db, m.GuildID)
+	if err == nil && previous != nil && previous.ICalURL != cfg.ICalURL {
+		if err := deleteCalendarSnapshot(db, m.GuildID); err != nil {
+			log.Printf("Fehler bei /timer setup: %v", err)
+		}
+	}
+	if err == nil && previous != nil {
+		cfg.BlockGap = previous.BlockGap
+		cfg.AttendanceReward = previous.AttendanceReward
+		if cfg.ProgressChannelID == "" {
+			cfg.ProgressChannelID = previous.ProgressChannelID
+		}
+	}
+
+	if err := saveGuildConfig(db, cfg); err != nil {
+		log.Printf("Fehler bei /timer setup: %v", err)
+		editResponse(s, m, "Fehler beim Speichern der Konfiguration.")
+		return
+	}
+
+	startTracker(s, db, cfg)
+
+	editResponse(s, m, fmt.Sprintf("✅ Timer eingerichtet: %d Termine gefunden, Vorlesungen werden in <#%s> angezeigt (Zeitzone %s).",
+		len(cal.Events()), cfg.ChannelID, cfg.Timezone))
+}
+
+func timerStatus(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	cfg, err := loadGuildConfig(db, m.GuildID)
+	if err != nil {
+		log.Printf("Fehler bei /timer status: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden der Konfiguration.")
+		return
+	}
+
+	if cfg == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	progressChannel := "nicht eingerichtet"
+	if cfg.ProgressChannelID != "" {
+		progressChannel = fmt.Sprintf("<#%s>", cfg.ProgressChannelID)
+	}
+
+	respondEphemeral(s, m, fmt.Sprintf("📅 Kalender: %s\nKanal: <#%s>\nFortschrittskanal: %s\nZeitzone: %s\nBlockpause: %d Min.\nBelohnung fürs Einchecken: %d Müller Coins",
+		cfg.ICalURL, cfg.ChannelID, progressChannel, cfg.Timezone, int(cfg.BlockGap.Minutes()), cfg.AttendanceReward))
+}
+
+// timerBlocks legt fest, wie lange eine Pause sein darf, damit Vorlesungen noch zum selben Block gehören
+func timerBlocks(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	cfg, err := loadGuildConfig(db, m.GuildID)
+	if err != nil {
+		log.Printf("Fehler bei /timer bloecke: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden der Konfiguration.")
+		return
+	}
+	if cfg == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	minutes := defaultBlockGapMinutes
+	for _, opt := range options {
+		if opt.Name == "pause" {
+			minutes = int(opt.IntValue())
+		}
+	}
+
+	if err := saveBlockGap(db, m.GuildID, minutes); err != nil {
+		log.Printf("Fehler bei /timer bloecke: %v", err)
+		respondEphemeral(s, m, "Fehler beim Speichern der Blockpause.")
+		return
+	}
+
+	// Laufenden Tracker direkt anpassen, die Blöcke werden beim nächsten Durchlauf neu gebildet
+	if t := trackerForGuild(m.GuildID); t != nil {
+		t.mu.Lock()
+		t.config.BlockGap = time.Duration(minutes) * time.Minute
+		t.mu.Unlock()
+	}
+
+	respondEphemeral(s, m, fmt.Sprintf("✅ Vorlesungen mit höchstens %d Minuten Pause dazwischen bilden jetzt einen Block.", minutes))
+}
+
+func timerFilter(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	t := trackerForGuild(m.GuildID)
+	if t == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		values[opt.Name] = opt
+	}
+
+	switch sub.Name {
+	case "add":
+		action := values["aktion"].StringValue()
+		field := values["feld"].StringValue()
+		pattern := values["muster"].StringValue()
+
+		if _, err := compileFilterPattern(pattern); err != nil {
+			respondEphemeral(s, m, fmt.Sprintf("Ungültiger regulärer Ausdruck: %v", err))
+			return
+		}
+
+		id, err := addFilterRule(db, m.GuildID, action, field, pattern)
+		if err != nil {
+			log.Printf("Fehler bei /timer filter add: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern der Filterregel.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("✅ Filterregel #%d gespeichert.", id))
+		t.applyFilterChange()
+
+	case "remove":
+		id := int(values["id"].IntValue())
+		deleted, err := deleteFilterRule(db, m.GuildID, id)
+		if err != nil {
+			log.Printf("Fehler bei /timer filter remove: %v", err)
+			respondEphemeral(s, m, "Fehler beim Löschen der Filterregel.")
+			return
+		}
+		if !deleted {
+			respondEphemeral(s, m, fmt.Sprintf("Filterregel #%d wurde nicht gefunden.", id))
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("🗑️ Filterregel #%d gelöscht.", id))
+		t.applyFilterChange()
+
+	case "dauer":
+		// Nicht angegebene Grenzen behalten ihren bisherigen Wert
+		current, err := loadLectureFilter(db, m.GuildID)
+		if err != nil {
+			log.Printf("Fehler bei /timer filter dauer: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden der Dauer.")
+			return
+		}
+		minMinutes, maxMinutes := int(current.MinDuration.Minutes()), int(current.MaxDuration.Minutes())
+		if opt, ok := values["min"]; ok {
+			minMinutes = int(opt.IntValue())
+		}
+		if opt, ok := values["max"]; ok {
+			maxMinutes = int(opt.IntValue())
+		}
+		if minMinutes >= maxMinutes {
+			respondEphemeral(s, m, "Die Mindestdauer muss kleiner als die Höchstdauer sein.")
+			return
+		}
+
+		if err := saveDurationLimits(db, m.GuildID, minMinutes, maxMinutes); err != nil {
+			log.Printf("Fehler bei /timer filter dauer: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern der Dauer.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("✅ Vorlesungen dauern jetzt mindestens %d und weniger als %d Minuten.", minMinutes, maxMinutes))
+		t.applyFilterChange()
+
+	case "list":
+		filter, err := loadLectureFilter(db, m.GuildID)
+		if err != nil {
+			log.Printf("Fehler bei /timer filter list: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden der Filterregeln.")
+			return
+		}
+		respondEphemeral(s, m, "📋 **Filterregeln**\n"+filter.describe())
+
+	default:
+		log.Printf("Unbekannter /timer filter Unterbefehl: %s", sub.Name)
+	}
+}
+
+func respondEphemeral(s *discordgo.Session, m *discordgo.InteractionCreate, content string) {
+	s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func editResponse(s *discordgo.Session, m *discordgo.InteractionCreate, content string) {
+	s.InteractionResponseEdit(m.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+	})
+}
+
+// timerReward legt fest, wie viele Müller Coins es fürs Einchecken in eine Vorlesung gibt
+func timerReward(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	cfg, err := loadGuildConfig(db, m.GuildID)
+	if err != nil {
+		log.Printf("Fehler bei /timer belohnung: %v", err)
+		respondEphemeral(s, m, "Fehler beim Laden der Konfiguration.")
+		return
+	}
+	if cfg == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	coins := defaultAttendanceReward
+	for _, opt := range options {
+		if opt.Name == "muenzen" {
+			coins = int(opt.IntValue())
+		}
+	}
+
+	if err := saveAttendanceReward(db, m.GuildID, coins); err != nil {
+		log.Printf("Fehler bei /timer belohnung: %v", err)
+		respondEphemeral(s, m, "Fehler beim Speichern der Belohnung.")
+		return
+	}
+
+	// Laufenden Tracker anpassen, damit der Einchecken-Button den neuen Betrag verwendet
+	if t := trackerForGuild(m.GuildID); t != nil {
+		t.mu.Lock()
+		t.config.AttendanceReward = coins
+		t.publishAttendance()
+		t.mu.Unlock()
+	}
+
+	if coins == 0 {
+		respondEphemeral(s, m, "✅ Einchecken in Vorlesungen wird nicht mehr belohnt.")
+		return
+	}
+	respondEphemeral(s, m, fmt.Sprintf("✅ Fürs Einchecken in eine Vorlesung gibt es jetzt %d Müller Coins.", coins))
+}
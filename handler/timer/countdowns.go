@@ -0,0 +1,280 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// Ein Embed kann höchstens 25 Felder anzeigen
+	maxCountdownsPerUser = 25
+
+	countdownCheckInterval = time.Minute
+)
+
+type countdown struct {
+	ID        int
+	UserID    string
+	Name      string
+	Target    time.Time
+	Notify    bool
+	CreatedAt time.Time
+	Timezone  string // Zeitzone der Gilde beim Anlegen, Countdowns selbst gehören zu keiner Gilde
+}
+
+// location liefert die beim Anlegen gespeicherte Zeitzone
+func (c countdown) location() *time.Location {
+	if loc, err := time.LoadLocation(c.Timezone); err == nil {
+		return loc
+	}
+	return GuildConfig{Timezone: defaultTimezone}.Location()
+}
+
+// CountdownCommand verarbeitet /countdown add|list|remove
+func CountdownCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range options[0].Options {
+		values[opt.Name] = opt
+	}
+
+	// Countdowns sind persönlich und funktionieren auch in Direktnachrichten
+	userID := interactionUserID(m)
+	loc := guildLocation(db, m.GuildID)
+
+	switch options[0].Name {
+	case "add":
+		target, err := parseDateInput(values["datum"].StringValue(), loc)
+		if err != nil {
+			respondEphemeral(s, m, "Ungültiges Datum. Erwartet wird TT.MM.JJJJ oder TT.MM.JJJJ HH:MM.")
+			return
+		}
+		if !target.After(time.Now()) {
+			respondEphemeral(s, m, "Das Datum muss in der Zukunft liegen.")
+			return
+		}
+
+		c := countdown{UserID: userID, Name: strings.TrimSpace(values["name"].StringValue()), Target: target, Timezone: loc.String()}
+		if opt, ok := values["dm"]; ok {
+			c.Notify = opt.BoolValue()
+		}
+
+		countdowns, err := loadCountdowns(db, userID)
+		if err != nil {
+			log.Printf("Fehler bei /countdown add: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden deiner Countdowns.")
+			return
+		}
+		if len(countdowns) >= maxCountdownsPerUser {
+			respondEphemeral(s, m, fmt.Sprintf("Du kannst höchstens %d Countdowns anlegen. Lösche zuerst einen mit /countdown remove.", maxCountdownsPerUser))
+			return
+		}
+
+		id, err := addCountdown(db, c)
+		if err != nil {
+			log.Printf("Fehler bei /countdown add: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern des Countdowns.")
+			return
+		}
+
+		reply := fmt.Sprintf("⏳ Countdown #%d **%s** bis %s angelegt.", id, c.Name, formatExamDate(target))
+		if c.Notify {
+			reply += " Du bekommst eine Direktnachricht, sobald er abgelaufen ist."
+		}
+		respondEphemeral(s, m, reply)
+
+	case "list":
+		countdowns, err := loadCountdowns(db, userID)
+		if err != nil {
+			log.Printf("Fehler bei /countdown list: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden deiner Countdowns.")
+			return
+		}
+		if len(countdowns) == 0 {
+			respondEphemeral(s, m, "Du hast keine Countdowns. Lege einen mit /countdown add an.")
+			return
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:     "⏳ Deine Countdowns",
+			Color:     0x00ccff,
+			Timestamp: time.Now().Format(time.RFC3339),
+		}
+		for _, c := range countdowns {
+			embed.Fields = append(embed.Fields, countdownField(c, loc))
+		}
+
+		s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+				Flags:  discordgo.MessageFlagsEphemeral,
+			},
+		})
+
+	case "remove":
+		id := int(values["id"].IntValue())
+		deleted, err := deleteCountdown(db, userID, id)
+		if err != nil {
+			log.Printf("Fehler bei /countdown remove: %v", err)
+			respondEphemeral(s, m, "Fehler beim Löschen des Countdowns.")
+			return
+		}
+		if !deleted {
+			respondEphemeral(s, m, fmt.Sprintf("Countdown #%d wurde nicht gefunden.", id))
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("🗑️ Countdown #%d gelöscht.", id))
+
+	default:
+		log.Printf("Unbekannter /countdown Unterbefehl: %s", options[0].Name)
+	}
+}
+
+// countdownField zeigt einen Countdown im selben Format wie die Fortschrittsanzeigen,
+// gemessen vom Anlegen bis zum Zieldatum
+func countdownField(c countdown, loc *time.Location) *discordgo.MessageEmbedField {
+	remaining, percentage := calculateProgress(c.CreatedAt, c.Target)
+	if percentage < 0 {
+		percentage = 0
+	}
+
+	status := fmt.Sprintf("noch %d Tage, %s Std. (<t:%d:R>)", remaining/(24*60), formatTimeFromMinutes(remaining%(24*60)), c.Target.Unix())
+	if remaining == 0 {
+		status = "🎉 Abgelaufen!"
+	}
+
+	name := fmt.Sprintf("#%d %s", c.ID, c.Name)
+	if c.Notify {
+		name += " 🔔"
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name: name,
+		Value: fmt.Sprintf("📅 %s\n%s\n%s %.1f %%",
+			formatExamDate(c.Target.In(loc)), status, createProgressBar(percentage, 20), percentage),
+		Inline: false,
+	}
+}
+
+// notifyCountdowns schickt für abgelaufene Countdowns mit Benachrichtigung eine Direktnachricht
+func notifyCountdowns(s *discordgo.Session, db *sql.DB) {
+	due, err := loadDueCountdowns(db, time.Now())
+	if err != nil {
+		fmt.Println("Fehler beim Laden der fälligen Countdowns:", err)
+		return
+	}
+
+	for _, c := range due {
+		// Erst vermerken, dann senden, siehe sendReminders
+		claimed, err := claimCountdownNotification(db, c.ID)
+		if err != nil {
+			fmt.Println("Fehler beim Vermerken des Countdowns:", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		err = sendDirectEmbed(s, c.UserID, &discordgo.MessageEmbed{
+			Title:       "🎉 Countdown abgelaufen: " + c.Name,
+			Description: fmt.Sprintf("Dein Countdown bis %s ist abgelaufen.", c.Target.In(c.location()).Format("02.01.2006 15:04")),
+			Color:       0x00ff00,
+			Timestamp:   time.Now().Format(time.RFC3339),
+		})
+		if err != nil {
+			fmt.Printf("Fehler beim Senden der Countdown-Nachricht an %s: %v\n", c.UserID, err)
+		}
+	}
+}
+
+// StartCountdownNotifier prüft jede Minute auf abgelaufene Countdowns
+func StartCountdownNotifier(s *discordgo.Session, db *sql.DB) {
+	ticker := time.NewTicker(countdownCheckInterval)
+	go func() {
+		notifyCountdowns(s, db)
+		for range ticker.C {
+			notifyCountdowns(s, db)
+		}
+	}()
+}
+
+func interactionUserID(m *discordgo.InteractionCreate) string {
+	if m.Member != nil && m.Member.User != nil {
+		return m.Member.User.ID
+	}
+	return m.User.ID
+}
+
+func loadCountdowns(db *sql.DB, userID string) ([]countdown, error) {
+	rows, err := db.Query(`SELECT id, user_id, name, target, notify, created_at, timezone FROM countdowns
+		WHERE user_id = $1 ORDER BY target`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Countdowns: %w", err)
+	}
+	defer rows.Close()
+	return scanCountdowns(rows)
+}
+
+func loadDueCountdowns(db *sql.DB, now time.Time) ([]countdown, error) {
+	rows, err := db.Query(`SELECT id, user_id, name, target, notify, created_at, timezone FROM countdowns
+		WHERE notify AND NOT notified AND target <= $1`, now)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Countdowns: %w", err)
+	}
+	defer rows.Close()
+	return scanCountdowns(rows)
+}
+
+func scanCountdowns(rows *sql.Rows) ([]countdown, error) {
+	var countdowns []countdown
+	for rows.Next() {
+		var c countdown
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Target, &c.Notify, &c.CreatedAt, &c.Timezone); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Countdowns: %w", err)
+		}
+		countdowns = append(countdowns, c)
+	}
+	return countdowns, rows.Err()
+}
+
+func addCountdown(db *sql.DB, c countdown) (int, error) {
+	var id int
+	err := db.QueryRow(`INSERT INTO countdowns (user_id, name, target, notify, timezone)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`, c.UserID, c.Name, c.Target, c.Notify, c.Timezone).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Speichern des Countdowns: %w", err)
+	}
+	return id, nil
+}
+
+func deleteCountdown(db *sql.DB, userID string, id int) (bool, error) {
+	result, err := db.Exec("DELETE FROM countdowns WHERE user_id = $1 AND id = $2", userID, id)
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Löschen des Countdowns: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// claimCountdownNotification vermerkt die Benachrichtigung und liefert false, falls das schon geschehen ist
+func claimCountdownNotification(db *sql.DB, id int) (bool, error) {
+	result, err := db.Exec("UPDATE countdowns SET notified = TRUE WHERE id = $1 AND NOT notified", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
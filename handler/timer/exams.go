@@ -0,0 +1,335 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/arran4/golang-ical"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	examSourceManual   = "manual"
+	examSourceCalendar = "calendar"
+
+	// Maximale Anzahl an Klausuren im Countdown-Embed
+	maxCountdownExams = 10
+)
+
+var (
+	// Kalendertermine mit diesen Begriffen im Titel werden als Klausur übernommen
+	examPattern = regexp.MustCompile(`(?i)klausur|prüfung|\bexam\b`)
+	// Vorbereitungstermine sind keine Klausuren
+	examExcludePattern = regexp.MustCompile(`(?i)vorbereitung|repetitorium`)
+)
+
+type exam struct {
+	ID       int
+	Module   string
+	Date     time.Time
+	Room     string
+	Source   string
+	EventUID string
+}
+
+// ExamCommand verarbeitet /exam add|list|remove
+func ExamCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range options[0].Options {
+		values[opt.Name] = opt
+	}
+
+	loc := guildLocation(db, m.GuildID)
+
+	switch options[0].Name {
+	case "add":
+		if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+			respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um Klausuren einzutragen.")
+			return
+		}
+
+		date, err := parseDateInput(values["datum"].StringValue(), loc)
+		if err != nil {
+			respondEphemeral(s, m, "Ungültiges Datum. Erwartet wird TT.MM.JJJJ oder TT.MM.JJJJ HH:MM.")
+			return
+		}
+
+		e := exam{Module: strings.TrimSpace(values["modul"].StringValue()), Date: date, Source: examSourceManual}
+		if opt, ok := values["raum"]; ok {
+			e.Room = strings.TrimSpace(opt.StringValue())
+		}
+
+		id, err := addExam(db, m.GuildID, e)
+		if err != nil {
+			log.Printf("Fehler bei /exam add: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern der Klausur.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("✅ Klausur #%d **%s** am %s eingetragen.", id, e.Module, formatExamDate(e.Date)))
+
+	case "list":
+		// Ab Tagesbeginn laden, damit Klausuren des heutigen Tages (oft nur mit Datum) sichtbar bleiben
+		today, _ := dayBounds(time.Now().In(loc))
+		exams, err := loadUpcomingExams(db, m.GuildID, today)
+		if err != nil {
+			log.Printf("Fehler bei /exam list: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden der Klausuren.")
+			return
+		}
+		if len(exams) == 0 {
+			respondEphemeral(s, m, "Es stehen keine Klausuren an. 🎉")
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString("📝 **Anstehende Klausuren**\n")
+		for _, e := range exams {
+			fmt.Fprintf(&b, "`#%d` **%s** – %s%s (<t:%d:R>)\n", e.ID, e.Module, formatExamDate(e.Date.In(loc)), examRoomSuffix(e), e.Date.Unix())
+		}
+		respondEphemeral(s, m, b.String())
+
+	case "remove":
+		if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+			respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um Klausuren zu entfernen.")
+			return
+		}
+
+		id := int(values["id"].IntValue())
+		removed, err := removeExam(db, m.GuildID, id)
+		if err != nil {
+			log.Printf("Fehler bei /exam remove: %v", err)
+			respondEphemeral(s, m, "Fehler beim Entfernen der Klausur.")
+			return
+		}
+		if !removed {
+			respondEphemeral(s, m, fmt.Sprintf("Klausur #%d wurde nicht gefunden.", id))
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("🗑️ Klausur #%d entfernt.", id))
+
+	default:
+		log.Printf("Unbekannter /exam Unterbefehl: %s", options[0].Name)
+	}
+}
+
+// parseDateInput liest ein Datum im Format TT.MM.JJJJ mit optionaler Uhrzeit
+func parseDateInput(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.ParseInLocation("02.01.2006 15:04", value, loc); err == nil {
+		return date, nil
+	}
+	return time.ParseInLocation("02.01.2006", value, loc)
+}
+
+func formatExamDate(date time.Time) string {
+	formatted := fmt.Sprintf("%s, %s", germanWeekdays[date.Weekday()][:2], date.Format("02.01.2006"))
+	if date.Hour() != 0 || date.Minute() != 0 {
+		formatted += " " + date.Format("15:04")
+	}
+	return formatted
+}
+
+func examRoomSuffix(e exam) string {
+	if e.Room == "" {
+		return ""
+	}
+	return " · " + e.Room
+}
+
+// guildLocation liefert die Zeitzone einer Gilde, ohne Konfiguration Europe/Berlin
+func guildLocation(db *sql.DB, guildID string) *time.Location {
+	cfg, err := loadGuildConfig(db, guildID)
+	if err != nil || cfg == nil {
+		cfg = &GuildConfig{GuildID: guildID, Timezone: defaultTimezone}
+	}
+	return cfg.Location()
+}
+
+// detectExams übernimmt Klausurtermine aus dem Kalender in die Klausurliste der Gilde
+func (t *lectureTracker) detectExams(cal *ics.Calendar) {
+	now := time.Now()
+
+	var exams []exam
+	for _, occ := range expandCalendar(cal, now.AddDate(0, 0, -1), now.Add(recurrenceHorizon)) {
+		if occ.End.Before(now) {
+			continue
+		}
+
+		summary := strings.TrimSpace(propertyValue(occ.Event, ics.ComponentPropertySummary))
+		if !examPattern.MatchString(summary) || examExcludePattern.MatchString(summary) {
+			continue
+		}
+		if strings.EqualFold(propertyValue(occ.Event, ics.ComponentPropertyStatus), "CANCELLED") {
+			continue
+		}
+
+		lecture := newLectureEvent(occ)
+		room := lecture.Room
+		if room == "" && lecture.Online {
+			room = "Online"
+		}
+
+		exams = append(exams, exam{
+			Module:   summary,
+			Date:     occ.Start,
+			Room:     room,
+			Source:   examSourceCalendar,
+			EventUID: occ.UID,
+		})
+	}
+
+	if err := syncCalendarExams(t.db, t.config.GuildID, exams, now); err != nil {
+		fmt.Println("Fehler beim Übernehmen der Klausuren aus dem Kalender:", err)
+	}
+}
+
+// syncCalendarExams gleicht die aus dem Kalender erkannten Klausuren mit der Datenbank ab.
+// Aus dem Kalender entfernte Klausuren werden gelöscht, per /exam remove ausgeblendete bleiben ausgeblendet.
+func syncCalendarExams(db *sql.DB, guildID string, exams []exam, now time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("fehler beim Starten der Transaktion: %w", err)
+	}
+	defer tx.Rollback()
+
+	current := make(map[string]bool, len(exams))
+	for _, e := range exams {
+		current[e.EventUID] = true
+	}
+
+	rows, err := tx.Query("SELECT event_uid FROM exams WHERE guild_id = $1 AND source = $2 AND exam_date > $3",
+		guildID, examSourceCalendar, now)
+	if err != nil {
+		return fmt.Errorf("fehler beim Laden der Klausuren: %w", err)
+	}
+	var stale []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			rows.Close()
+			return fmt.Errorf("fehler beim Lesen der Klausuren: %w", err)
+		}
+		if !current[uid] {
+			stale = append(stale, uid)
+		}
+	}
+	rows.Close()
+
+	for _, uid := range stale {
+		if _, err := tx.Exec("DELETE FROM exams WHERE guild_id = $1 AND event_uid = $2", guildID, uid); err != nil {
+			return fmt.Errorf("fehler beim Löschen der Klausur: %w", err)
+		}
+	}
+
+	for _, e := range exams {
+		_, err := tx.Exec(`INSERT INTO exams (guild_id, module, exam_date, room, source, event_uid)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (guild_id, event_uid) DO UPDATE
+			SET module = EXCLUDED.module, exam_date = EXCLUDED.exam_date, room = EXCLUDED.room`,
+			guildID, e.Module, e.Date, e.Room, e.Source, e.EventUID)
+		if err != nil {
+			return fmt.Errorf("fehler beim Speichern der Klausur: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func addExam(db *sql.DB, guildID string, e exam) (int, error) {
+	var id int
+	err := db.QueryRow(`INSERT INTO exams (guild_id, module, exam_date, room, source)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`, guildID, e.Module, e.Date, e.Room, e.Source).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Speichern der Klausur: %w", err)
+	}
+	return id, nil
+}
+
+// removeExam löscht eine eingetragene Klausur, erkannte Klausuren werden nur ausgeblendet,
+// damit sie beim nächsten Kalenderabruf nicht wieder auftauchen
+func removeExam(db *sql.DB, guildID string, id int) (bool, error) {
+	result, err := db.Exec("DELETE FROM exams WHERE guild_id = $1 AND id = $2 AND source = $3", guildID, id, examSourceManual)
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Löschen der Klausur: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected > 0 {
+		return affected > 0, err
+	}
+
+	result, err = db.Exec("UPDATE exams SET dismissed = TRUE WHERE guild_id = $1 AND id = $2 AND NOT dismissed", guildID, id)
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Ausblenden der Klausur: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func loadUpcomingExams(db *sql.DB, guildID string, from time.Time) ([]exam, error) {
+	rows, err := db.Query(`SELECT id, module, exam_date, room, source FROM exams
+		WHERE guild_id = $1 AND exam_date >= $2 AND NOT dismissed ORDER BY exam_date`, guildID, from)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Klausuren: %w", err)
+	}
+	defer rows.Close()
+
+	var exams []exam
+	for rows.Next() {
+		var e exam
+		if err := rows.Scan(&e.ID, &e.Module, &e.Date, &e.Room, &e.Source); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Klausuren: %w", err)
+		}
+		exams = append(exams, e)
+	}
+	return exams, rows.Err()
+}
+
+// examCountdownEmbed erstellt das Countdown-Embed für die nächsten Klausuren
+func examCountdownEmbed(exams []exam, loc *time.Location) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     "📝 Klausuren-Countdown",
+		Color:     0xff9900,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if len(exams) == 0 {
+		embed.Description = "Aktuell stehen keine Klausuren an. 🎉"
+		return embed
+	}
+
+	embed.Description = fmt.Sprintf("Noch %d Klausur(en) bis zur Freiheit:", len(exams))
+	for i, e := range exams {
+		if i == maxCountdownExams {
+			embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("... und %d weitere, siehe /exam list", len(exams)-maxCountdownExams)}
+			break
+		}
+
+		days := int(time.Until(e.Date).Hours() / 24)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   e.Module,
+			Value:  fmt.Sprintf("📅 %s%s\n⏳ noch %d Tage (<t:%d:R>)", formatExamDate(e.Date.In(loc)), examRoomSuffix(e), days, e.Date.Unix()),
+			Inline: false,
+		})
+	}
+	return embed
+}
+
+// updateExamCountdown aktualisiert das angeheftete Klausuren-Countdown-Embed im Fortschrittskanal
+func updateExamCountdown(s *discordgo.Session, db *sql.DB, cfg GuildConfig) {
+	today, _ := dayBounds(time.Now().In(cfg.Location()))
+	exams, err := loadUpcomingExams(db, cfg.GuildID, today)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Klausuren:", err)
+		return
+	}
+
+	publishProgressEmbed(s, db, cfg, progressMessageExams, examCountdownEmbed(exams, cfg.Location()), nil, true)
+}
@@ -0,0 +1,87 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const defaultTimezone = "Europe/Berlin"
+
+// GuildConfig beschreibt die Kalenderquelle und den Zielkanal einer Gilde
+type GuildConfig struct {
+	GuildID   string
+	ICalURL   string
+	ChannelID string
+	Timezone  string
+	// ProgressChannelID ist der Kanal für Studien-, Semester- und Klausur-Fortschritt (optional)
+	ProgressChannelID string
+	// BlockGap ist die maximale Pause, bis zu der Vorlesungen zum selben Block gehören
+	BlockGap time.Duration
+	// AttendanceReward ist die Anzahl Müller Coins, die es fürs Einchecken in eine Vorlesung gibt
+	AttendanceReward int
+}
+
+// Location lädt die konfigurierte Zeitzone, bei Fehlern wird Europe/Berlin verwendet
+func (c GuildConfig) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err == nil {
+		return loc
+	}
+
+	fmt.Printf("Warnung: Konnte Zeitzone %q für Gilde %s nicht laden: %v\n", c.Timezone, c.GuildID, err)
+	loc, err = time.LoadLocation(defaultTimezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func loadGuildConfigs(db *sql.DB) ([]GuildConfig, error) {
+	rows, err := db.Query("SELECT guild_id, ical_url, channel_id, timezone, progress_channel_id, block_gap_minutes, attendance_reward FROM timer_config")
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Timer-Konfigurationen: %w", err)
+	}
+	defer rows.Close()
+
+	var configs []GuildConfig
+	for rows.Next() {
+		var cfg GuildConfig
+		var blockGap int
+		if err := rows.Scan(&cfg.GuildID, &cfg.ICalURL, &cfg.ChannelID, &cfg.Timezone, &cfg.ProgressChannelID, &blockGap, &cfg.AttendanceReward); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Timer-Konfiguration: %w", err)
+		}
+		cfg.BlockGap = time.Duration(blockGap) * time.Minute
+		configs = append(configs, cfg)
+	}
+
+	return configs, rows.Err()
+}
+
+func loadGuildConfig(db *sql.DB, guildID string) (*GuildConfig, error) {
+	cfg := GuildConfig{GuildID: guildID}
+	var blockGap int
+	err := db.QueryRow("SELECT ical_url, channel_id, timezone, progress_channel_id, block_gap_minutes, attendance_reward FROM timer_config WHERE guild_id = $1", guildID).
+		Scan(&cfg.ICalURL, &cfg.ChannelID, &cfg.Timezone, &cfg.ProgressChannelID, &blockGap, &cfg.AttendanceReward)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Timer-Konfiguration: %w", err)
+	}
+	cfg.BlockGap = time.Duration(blockGap) * time.Minute
+	return &cfg, nil
+}
+
+func saveGuildConfig(db *sql.DB, cfg GuildConfig) error {
+	_, err := db.Exec(`INSERT INTO timer_config (guild_id, ical_url, channel_id, timezone, progress_channel_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (guild_id) DO UPDATE
+		SET ical_url = EXCLUDED.ical_url, channel_id = EXCLUDED.channel_id, timezone = EXCLUDED.timezone,
+			progress_channel_id = EXCLUDED.progress_channel_id, updated_at = CURRENT_TIMESTAMP`,
+		cfg.GuildID, cfg.ICalURL, cfg.ChannelID, cfg.Timezone, cfg.ProgressChannelID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Timer-Konfiguration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package timer
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultWeekDays = 7
+
+// Discord erlaubt maximal 1024 Zeichen pro Feld und 6000 Zeichen pro Embed
+const (
+	maxLectureFieldLength = 1000
+	maxLectureDaysLength  = 5000
+)
+
+var germanWeekdays = []string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"}
+
+// NextCommand zeigt die nächste Vorlesung mit Countdown an
+func NextCommand(s *discordgo.Session, m *discordgo.InteractionCreate) {
+	t := trackerForGuild(m.GuildID)
+	if t == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	now := time.Now()
+	lectures, err := t.upcomingLectures(now, now.AddDate(0, 0, 30))
+	if err != nil {
+		log.Printf("Fehler bei /next: %v", err)
+		respondEphemeral(s, m, "Fehler beim Abrufen des Kalenders.")
+		return
+	}
+
+	if len(lectures) == 0 {
+		respondEphemeral(s, m, "In den nächsten 30 Tagen stehen keine Vorlesungen an. 🎉")
+		return
+	}
+
+	next := lectures[0]
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Beginn",
+			Value:  fmt.Sprintf("%s, %s", germanWeekdays[next.Start.Weekday()], next.Start.Format("02.01.2006 15:04")),
+			Inline: true,
+		},
+		{
+			Name:   "Ende",
+			Value:  next.End.Format("15:04"),
+			Inline: true,
+		},
+		{
+			Name:   "Countdown",
+			Value:  fmt.Sprintf("<t:%d:R> (%s)", next.Start.Unix(), formatTimeFromMinutes(int(time.Until(next.Start).Minutes()))),
+			Inline: false,
+		},
+	}
+	fields = append(fields, lectureDetailFields(next)...)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Nächste Vorlesung: " + next.Name,
+		Description: fmt.Sprintf("Dauer: %.0f Min.", next.End.Sub(next.Start).Minutes()),
+		Color:       0x00ccff,
+		Fields:      fields,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+
+	err = s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+	if err != nil {
+		log.Printf("Fehler beim Senden von /next: %v", err)
+	}
+}
+
+// TodayCommand zeigt alle Vorlesungen des heutigen Tages an
+func TodayCommand(s *discordgo.Session, m *discordgo.InteractionCreate) {
+	lectureListCommand(s, m, 1, "📚 Vorlesungen heute")
+}
+
+// WeekCommand zeigt die Vorlesungen der nächsten Tage an (Standard: 7)
+func WeekCommand(s *discordgo.Session, m *discordgo.InteractionCreate) {
+	days := defaultWeekDays
+	for _, opt := range m.ApplicationCommandData().Options {
+		if opt.Name == "tage" {
+			days = int(opt.IntValue())
+		}
+	}
+
+	lectureListCommand(s, m, days, fmt.Sprintf("📚 Vorlesungen der nächsten %d Tage", days))
+}
+
+func lectureListCommand(s *discordgo.Session, m *discordgo.InteractionCreate, days int, title string) {
+	t := trackerForGuild(m.GuildID)
+	if t == nil {
+		respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+		return
+	}
+
+	// Ab Beginn des heutigen Tages, damit laufende Vorlesungen mit angezeigt werden
+	now := time.Now().In(t.location)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, t.location)
+	to := from.AddDate(0, 0, days)
+
+	lectures, err := t.upcomingLectures(from, to)
+	if err != nil {
+		log.Printf("Fehler beim Abrufen der Vorlesungen: %v", err)
+		respondEphemeral(s, m, "Fehler beim Abrufen des Kalenders.")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:     title,
+		Color:     0x00ccff,
+		Fields:    formatLectureDays(lectures),
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if len(lectures) == 0 {
+		embed.Description = "Keine Vorlesungen gefunden. 🎉"
+	}
+
+	err = s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+	if err != nil {
+		log.Printf("Fehler beim Senden der Vorlesungsliste: %v", err)
+		respondEphemeral(s, m, "Die Vorlesungsliste konnte nicht gesendet werden.")
+	}
+}
+
+// formatLectureDays gruppiert die Vorlesungen nach Tag, ein Embed-Feld pro Tag. Was nicht
+// mehr in die Feld- bzw. Embed-Grenzen von Discord passt, wird als "... und N weitere" gezählt.
+func formatLectureDays(lectures []LectureEvent) []*discordgo.MessageEmbedField {
+	var fields []*discordgo.MessageEmbedField
+	var current *discordgo.MessageEmbedField
+	var currentDay string
+	total, omitted := 0, 0
+
+	for _, lecture := range lectures {
+		day := lecture.Start.Format("2006-01-02")
+		if current == nil || day != currentDay {
+			current = &discordgo.MessageEmbedField{
+				Name: fmt.Sprintf("%s, %s", germanWeekdays[lecture.Start.Weekday()], lecture.Start.Format("02.01.")),
+			}
+			fields = append(fields, current)
+			currentDay = day
+			total += len(current.Name)
+		}
+
+		line := fmt.Sprintf("🕐 %s - %s **%s**", lecture.Start.Format("15:04"), lecture.End.Format("15:04"), lecture.Name)
+		line += lectureShortInfo(lecture)
+		if len(current.Value)+len(line)+1 > maxLectureFieldLength || total+len(line)+1 > maxLectureDaysLength {
+			omitted++
+			continue
+		}
+		current.Value = strings.TrimPrefix(current.Value+"\n"+line, "\n")
+		total += len(line) + 1
+	}
+
+	// Tage ohne Platz für auch nur eine Vorlesung nicht als leere Felder senden
+	kept := fields[:0]
+	for _, field := range fields {
+		if field.Value != "" {
+			kept = append(kept, field)
+		}
+	}
+	fields = kept
+
+	if omitted > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "…",
+			Value: fmt.Sprintf("und %d weitere Vorlesung(en)", omitted),
+		})
+	}
+	return fields
+}
@@ -0,0 +1,215 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	filterInclude = "include"
+	filterExclude = "exclude"
+
+	filterFieldSummary  = "titel"
+	filterFieldCategory = "kategorie"
+	filterFieldLocation = "ort"
+
+	// Standardgrenzen entsprechen der bisherigen 4-Stunden-Regel
+	defaultMinLectureMinutes = 0
+	defaultMaxLectureMinutes = 240
+)
+
+// filterRule ist eine Regel auf Titel, Kategorie oder Ort eines Termins
+type filterRule struct {
+	ID      int
+	Action  string
+	Field   string
+	Pattern string
+	regex   *regexp.Regexp
+}
+
+// lectureFilter entscheidet, welche Kalendertermine als Vorlesung gelten:
+//   - passt eine exclude-Regel, wird der Termin immer ignoriert
+//   - passt eine include-Regel, gilt der Termin unabhängig von der Dauer als Vorlesung
+//   - sonst entscheidet die erlaubte Dauer [MinDuration, MaxDuration)
+type lectureFilter struct {
+	MinDuration time.Duration
+	MaxDuration time.Duration
+	Rules       []filterRule
+}
+
+func defaultLectureFilter() lectureFilter {
+	return lectureFilter{
+		MinDuration: defaultMinLectureMinutes * time.Minute,
+		MaxDuration: defaultMaxLectureMinutes * time.Minute,
+	}
+}
+
+// Allows prüft, ob ein Termin als Vorlesung angezeigt wird
+func (f lectureFilter) Allows(lecture LectureEvent) bool {
+	included := false
+	for _, rule := range f.Rules {
+		if !rule.matches(lecture) {
+			continue
+		}
+		if rule.Action == filterExclude {
+			return false
+		}
+		included = true
+	}
+	if included {
+		return true
+	}
+
+	duration := lecture.End.Sub(lecture.Start)
+	return duration >= f.MinDuration && duration < f.MaxDuration
+}
+
+func (r filterRule) matches(lecture LectureEvent) bool {
+	switch r.Field {
+	case filterFieldSummary:
+		return r.regex.MatchString(lecture.Name)
+	case filterFieldCategory:
+		for _, category := range lecture.Categories {
+			if r.regex.MatchString(category) {
+				return true
+			}
+		}
+		return false
+	case filterFieldLocation:
+		return r.regex.MatchString(lecture.Location)
+	}
+	return false
+}
+
+// compileFilterPattern kompiliert ein Muster ohne Beachtung der Groß-/Kleinschreibung
+func compileFilterPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+func loadLectureFilter(db *sql.DB, guildID string) (lectureFilter, error) {
+	filter := defaultLectureFilter()
+
+	var minMinutes, maxMinutes int
+	err := db.QueryRow("SELECT min_duration_minutes, max_duration_minutes FROM timer_config WHERE guild_id = $1", guildID).
+		Scan(&minMinutes, &maxMinutes)
+	if err != nil && err != sql.ErrNoRows {
+		return filter, fmt.Errorf("fehler beim Laden der Filtergrenzen: %w", err)
+	}
+	if err == nil {
+		filter.MinDuration = time.Duration(minMinutes) * time.Minute
+		filter.MaxDuration = time.Duration(maxMinutes) * time.Minute
+	}
+
+	rules, err := loadFilterRules(db, guildID)
+	if err != nil {
+		return filter, err
+	}
+	filter.Rules = rules
+	return filter, nil
+}
+
+func loadFilterRules(db *sql.DB, guildID string) ([]filterRule, error) {
+	rows, err := db.Query("SELECT id, action, field, pattern FROM lecture_filters WHERE guild_id = $1 ORDER BY id", guildID)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Filterregeln: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []filterRule
+	for rows.Next() {
+		var rule filterRule
+		if err := rows.Scan(&rule.ID, &rule.Action, &rule.Field, &rule.Pattern); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Filterregeln: %w", err)
+		}
+		rule.regex, err = compileFilterPattern(rule.Pattern)
+		if err != nil {
+			fmt.Printf("Warnung: Ungültige Filterregel %d (%q) wird ignoriert: %v\n", rule.ID, rule.Pattern, err)
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func addFilterRule(db *sql.DB, guildID, action, field, pattern string) (int, error) {
+	var id int
+	err := db.QueryRow(`INSERT INTO lecture_filters (guild_id, action, field, pattern)
+		VALUES ($1, $2, $3, $4) RETURNING id`, guildID, action, field, pattern).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Speichern der Filterregel: %w", err)
+	}
+	return id, nil
+}
+
+func deleteFilterRule(db *sql.DB, guildID string, id int) (bool, error) {
+	result, err := db.Exec("DELETE FROM lecture_filters WHERE guild_id = $1 AND id = $2", guildID, id)
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Löschen der Filterregel: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func saveDurationLimits(db *sql.DB, guildID string, minMinutes, maxMinutes int) error {
+	_, err := db.Exec(`UPDATE timer_config SET min_duration_minutes = $1, max_duration_minutes = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE guild_id = $3`, minMinutes, maxMinutes, guildID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Filtergrenzen: %w", err)
+	}
+	return nil
+}
+
+// describe liefert eine lesbare Beschreibung aller Regeln für /timer filter list
+func (f lectureFilter) describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "⏱️ Dauer: mindestens %d Min., weniger als %d Min.\n",
+		int(f.MinDuration.Minutes()), int(f.MaxDuration.Minutes()))
+
+	if len(f.Rules) == 0 {
+		b.WriteString("Keine Regeln definiert.")
+		return b.String()
+	}
+
+	for _, rule := range f.Rules {
+		icon := "✅"
+		if rule.Action == filterExclude {
+			icon = "🚫"
+		}
+		fmt.Fprintf(&b, "`#%d` %s %s ~ `%s`\n", rule.ID, icon, rule.Field, rule.Pattern)
+	}
+	return b.String()
+}
+
+// reloadFilter lädt die Filterregeln des Trackers neu
+func (t *lectureTracker) reloadFilter() {
+	filter, err := loadLectureFilter(t.db, t.config.GuildID)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Filterregeln:", err)
+	}
+
+	t.filterMu.Lock()
+	t.filter = filter
+	t.filterMu.Unlock()
+}
+
+// applyFilterChange lädt geänderte Regeln und übernimmt sie ohne Änderungsmeldung in den Snapshot.
+// Wartet auf t.mu, daher erst nach der Antwort auf die Interaktion aufrufen.
+func (t *lectureTracker) applyFilterChange() {
+	t.reloadFilter()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cal, err := t.cachedCalendar()
+	if err != nil {
+		return
+	}
+
+	current := t.snapshotLectures(cal, time.Now())
+	if err := saveCalendarSnapshot(t.db, t.config.GuildID, current); err != nil {
+		fmt.Println("Fehler beim Speichern des Kalender-Snapshots:", err)
+	}
+}
@@ -0,0 +1,131 @@
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func testFilterRule(t *testing.T, action, field, pattern string) filterRule {
+	t.Helper()
+
+	regex, err := compileFilterPattern(pattern)
+	if err != nil {
+		t.Fatalf("Muster %q nicht kompilierbar: %v", pattern, err)
+	}
+	return filterRule{Action: action, Field: field, Pattern: pattern, regex: regex}
+}
+
+func testLecture(name, location string, duration time.Duration, categories ...string) LectureEvent {
+	start := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	return LectureEvent{
+		Name:       name,
+		Location:   location,
+		Categories: categories,
+		Start:      start,
+		End:        start.Add(duration),
+	}
+}
+
+func TestLectureFilterAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []filterRule
+		lecture LectureEvent
+		want    bool
+	}{
+		{
+			name:    "normale Vorlesung",
+			lecture: testLecture("Mathematik", "A101", 90*time.Minute),
+			want:    true,
+		},
+		{
+			name:    "ganztägiger Termin",
+			lecture: testLecture("Projekttag", "", 8*time.Hour),
+			want:    false,
+		},
+		{
+			name:    "genau an der Obergrenze",
+			lecture: testLecture("Blockveranstaltung", "", defaultMaxLectureMinutes*time.Minute),
+			want:    false,
+		},
+		{
+			name:    "Exclude auf Titel",
+			rules:   []filterRule{testFilterRule(t, filterExclude, filterFieldSummary, "^sprechstunde")},
+			lecture: testLecture("Sprechstunde Müller", "B2", 60*time.Minute),
+			want:    false,
+		},
+		{
+			name:    "Exclude ohne Treffer",
+			rules:   []filterRule{testFilterRule(t, filterExclude, filterFieldSummary, "^sprechstunde")},
+			lecture: testLecture("Mathematik", "A101", 90*time.Minute),
+			want:    true,
+		},
+		{
+			name:    "Include erlaubt lange Termine",
+			rules:   []filterRule{testFilterRule(t, filterInclude, filterFieldSummary, "klausur")},
+			lecture: testLecture("KLAUSUR Mathematik", "Audimax", 5*time.Hour),
+			want:    true,
+		},
+		{
+			name: "Exclude gewinnt gegen Include",
+			rules: []filterRule{
+				testFilterRule(t, filterInclude, filterFieldSummary, "klausur"),
+				testFilterRule(t, filterExclude, filterFieldLocation, "online"),
+			},
+			lecture: testLecture("Klausur Mathematik", "Online", 5*time.Hour),
+			want:    false,
+		},
+		{
+			name:    "Kategorie",
+			rules:   []filterRule{testFilterRule(t, filterExclude, filterFieldCategory, "^wahlfach$")},
+			lecture: testLecture("Japanisch", "C3", 90*time.Minute, "Sprachen", "Wahlfach"),
+			want:    false,
+		},
+		{
+			name:    "Kategorie ohne Treffer",
+			rules:   []filterRule{testFilterRule(t, filterExclude, filterFieldCategory, "^wahlfach$")},
+			lecture: testLecture("Japanisch", "C3", 90*time.Minute, "Wahlfach Sprachen"),
+			want:    true,
+		},
+		{
+			name:    "Ort",
+			rules:   []filterRule{testFilterRule(t, filterInclude, filterFieldLocation, `^A\d+`)},
+			lecture: testLecture("Praktikum", "a204", 6*time.Hour),
+			want:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := defaultLectureFilter()
+			filter.Rules = tt.rules
+
+			if got := filter.Allows(tt.lecture); got != tt.want {
+				t.Errorf("Allows(%q) = %v, erwartet %v", tt.lecture.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLectureFilterDurationLimits(t *testing.T) {
+	filter := lectureFilter{MinDuration: 30 * time.Minute, MaxDuration: 3 * time.Hour}
+
+	tests := []struct {
+		duration time.Duration
+		want     bool
+	}{
+		{duration: 15 * time.Minute, want: false},
+		{duration: 30 * time.Minute, want: true},
+		{duration: 90 * time.Minute, want: true},
+		{duration: 3*time.Hour - time.Minute, want: true},
+		{duration: 3 * time.Hour, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duration.String(), func(t *testing.T) {
+			if got := filter.Allows(testLecture("Mathematik", "", tt.duration)); got != tt.want {
+				t.Errorf("Allows() bei %v = %v, erwartet %v", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package timer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/arran4/golang-ical"
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	urlPattern      = regexp.MustCompile(`https?://[^\s<>"]+`)
+	lecturerPattern = regexp.MustCompile(`(?i)^\s*(?:dozent(?:in)?|dozierende[rn]?|lehrende[rn]?|referent(?:in)?|lecturer|teacher|prof(?:essor)?)\s*[:=]\s*(.+)$`)
+	titlePattern    = regexp.MustCompile(`^\s*((?:Prof\.|Dr\.)\s*.+)$`)
+
+	// Bekannte Anbieter für Online-Vorlesungen
+	meetingHosts = []string{"zoom.us", "teams.microsoft.com", "teams.live.com", "webex.com", "meet.google.com", "bigbluebutton", "meet.jit.si"}
+	onlineWords  = []string{"online", "digital", "remote", "zoom", "teams", "webex"}
+)
+
+// lectureMetadata enthält die aus LOCATION und DESCRIPTION gelesenen Zusatzinfos
+type lectureMetadata struct {
+	Room       string
+	Lecturer   string
+	Online     bool
+	MeetingURL string
+}
+
+// parseLectureMetadata liest Raum, Dozent und Online-Link aus den Freitextfeldern eines Termins
+func parseLectureMetadata(location, description, url string) lectureMetadata {
+	var meta lectureMetadata
+
+	// Meeting-Link: bevorzugt bekannte Anbieter, sonst der erste gefundene Link
+	var links []string
+	links = append(links, urlPattern.FindAllString(location, -1)...)
+	links = append(links, urlPattern.FindAllString(description, -1)...)
+	if url != "" {
+		links = append(links, url)
+	}
+	for _, link := range links {
+		if isMeetingLink(link) {
+			meta.MeetingURL = link
+			break
+		}
+	}
+	if meta.MeetingURL == "" && len(links) > 0 {
+		meta.MeetingURL = links[0]
+	}
+
+	// Raum: LOCATION ohne Links und ohne "Online"-Hinweise
+	var rooms []string
+	for _, part := range strings.FieldsFunc(urlPattern.ReplaceAllString(location, ""), func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	}) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if containsAny(strings.ToLower(part), onlineWords) {
+			meta.Online = true
+			continue
+		}
+		rooms = append(rooms, part)
+	}
+	meta.Room = strings.Join(rooms, ", ")
+
+	if meta.MeetingURL != "" && (meta.Room == "" || isMeetingLink(meta.MeetingURL)) {
+		meta.Online = true
+	}
+
+	// Dozent: beschriftete Zeile ("Dozent: ...") oder eine Zeile mit akademischem Titel
+	for _, line := range strings.Split(description, "\n") {
+		if match := lecturerPattern.FindStringSubmatch(line); match != nil {
+			meta.Lecturer = strings.TrimSpace(match[1])
+			break
+		}
+		if meta.Lecturer == "" {
+			if match := titlePattern.FindStringSubmatch(line); match != nil && !urlPattern.MatchString(line) {
+				meta.Lecturer = strings.TrimSpace(match[1])
+			}
+		}
+	}
+
+	return meta
+}
+
+func isMeetingLink(link string) bool {
+	return containsAny(strings.ToLower(link), meetingHosts)
+}
+
+func containsAny(value string, needles []string) bool {
+	for _, needle := range needles {
+		if strings.Contains(value, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// newLectureEvent baut eine Vorlesung inklusive Metadaten aus einem Kalendertermin
+func newLectureEvent(occ occurrence) LectureEvent {
+	location := propertyValue(occ.Event, ics.ComponentPropertyLocation)
+	meta := parseLectureMetadata(location,
+		propertyValue(occ.Event, ics.ComponentPropertyDescription),
+		propertyValue(occ.Event, ics.ComponentPropertyUrl))
+
+	return LectureEvent{
+		UID:        occ.UID,
+		Name:       propertyValue(occ.Event, ics.ComponentPropertySummary),
+		Location:   location,
+		Room:       meta.Room,
+		Lecturer:   meta.Lecturer,
+		Online:     meta.Online,
+		MeetingURL: meta.MeetingURL,
+		Categories: eventCategories(occ.Event),
+	}
+}
+
+func eventCategories(event *ics.VEvent) []string {
+	var categories []string
+	for _, prop := range event.GetProperties(ics.ComponentPropertyCategories) {
+		for _, category := range strings.Split(prop.Value, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
+
+// lectureDetailFields liefert die Embed-Felder für Raum, Dozent und Online-Link
+func lectureDetailFields(lecture LectureEvent) []*discordgo.MessageEmbedField {
+	var fields []*discordgo.MessageEmbedField
+
+	room := lecture.Room
+	if room == "" && lecture.Online {
+		room = "Online"
+	} else if lecture.Online {
+		room += " (hybrid)"
+	}
+	if room != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Raum", Value: room, Inline: true})
+	}
+	if lecture.Lecturer != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Dozent", Value: lecture.Lecturer, Inline: true})
+	}
+	if lecture.MeetingURL != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Online-Link",
+			Value:  fmt.Sprintf("[Beitreten](%s)", lecture.MeetingURL),
+			Inline: false,
+		})
+	}
+	return fields
+}
+
+// lectureShortInfo liefert eine kompakte Zusatzinfo für Listen, z.B. "(A1.23, Müller)"
+func lectureShortInfo(lecture LectureEvent) string {
+	var parts []string
+	if lecture.Room != "" {
+		parts = append(parts, lecture.Room)
+	}
+	if lecture.Online {
+		parts = append(parts, "💻 Online")
+	}
+	if lecture.Lecturer != "" {
+		parts = append(parts, lecture.Lecturer)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
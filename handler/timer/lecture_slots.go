@@ -0,0 +1,187 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Standardabstand, bis zu dem aufeinanderfolgende Vorlesungen als ein Block gelten
+const defaultBlockGapMinutes = 30
+
+// lectureBlock fasst überlappende oder direkt aufeinanderfolgende Vorlesungen eines Tages zusammen
+type lectureBlock struct {
+	Slot     LectureSlot
+	Start    time.Time
+	End      time.Time
+	Lectures []LectureEvent
+}
+
+// buildDayBlocks bildet aus den nach Beginn sortierten Vorlesungen eines Tages die Blöcke.
+// Beginnt eine Vorlesung höchstens gap nach dem Ende des vorherigen Blocks, gehört sie noch dazu.
+func buildDayBlocks(lectures []LectureEvent, gap time.Duration) []lectureBlock {
+	var blocks []lectureBlock
+	for _, lecture := range lectures {
+		if n := len(blocks); n > 0 && !lecture.Start.After(blocks[n-1].End.Add(gap)) {
+			block := &blocks[n-1]
+			block.Lectures = append(block.Lectures, lecture)
+			if lecture.End.After(block.End) {
+				block.End = lecture.End
+			}
+			continue
+		}
+		blocks = append(blocks, lectureBlock{Start: lecture.Start, End: lecture.End, Lectures: []LectureEvent{lecture}})
+	}
+
+	// Blöcke nach Tageszeit benennen, mehrere Blöcke im selben Zeitraum werden durchnummeriert
+	counts := make(map[LectureSlot]int)
+	for i := range blocks {
+		period := slotPeriod(blocks[i].Start)
+		counts[period]++
+		blocks[i].Slot = period
+		if counts[period] > 1 {
+			blocks[i].Slot = LectureSlot(fmt.Sprintf("%s-%d", period, counts[period]))
+		}
+	}
+	return blocks
+}
+
+// Grenzen der Tageszeiten in lokaler Zeit: Blöcke, die vor afternoonStartHour beginnen, gehören
+// zum Vormittag, ab eveningStartHour zum Abend
+const (
+	afternoonStartHour = 12
+	eveningStartHour   = 17
+)
+
+// slotPeriod ordnet einen Block anhand seines Beginns einer Tageszeit zu
+func slotPeriod(start time.Time) LectureSlot {
+	switch hour := start.Hour(); {
+	case hour < afternoonStartHour:
+		return Morning
+	case hour < eveningStartHour:
+		return Afternoon
+	default:
+		return Evening
+	}
+}
+
+// slotLabel liefert den Anzeigenamen eines Blocks, z.B. "Vormittag" oder "Abend 2"
+func slotLabel(slot LectureSlot) string {
+	labels := map[LectureSlot]string{
+		Morning:   "Vormittag",
+		Afternoon: "Nachmittag",
+		Evening:   "Abend",
+	}
+
+	period, number, _ := strings.Cut(string(slot), "-")
+	label, ok := labels[LectureSlot(period)]
+	if !ok {
+		return "Kein Block"
+	}
+	if number != "" {
+		label += " " + number
+	}
+	return label
+}
+
+// blockForLecture ermittelt den Block, zu dem eine Vorlesung am jeweiligen Tag gehört
+func (t *lectureTracker) blockForLecture(lecture *LectureEvent) *lectureBlock {
+	startOfDay, endOfDay := dayBounds(lecture.Start.In(t.location))
+	lectures, err := t.upcomingLectures(startOfDay, endOfDay)
+	if err != nil {
+		fmt.Println("Fehler beim Ermitteln des Vorlesungsblocks:", err)
+		return nil
+	}
+
+	for _, block := range buildDayBlocks(lectures, t.config.BlockGap) {
+		if !lecture.Start.Before(block.Start) && !lecture.Start.After(block.End) {
+			return &block
+		}
+	}
+	return nil
+}
+
+// runningLectures liefert alle Vorlesungen, die zum Zeitpunkt now laufen
+func (t *lectureTracker) runningLectures(now time.Time) ([]LectureEvent, error) {
+	cal, err := t.cachedCalendar()
+	if err != nil {
+		return nil, err
+	}
+
+	var running []LectureEvent
+	for _, lecture := range t.lecturesFromCalendar(cal) {
+		if now.After(lecture.Start) && now.Before(lecture.End) {
+			running = append(running, lecture)
+		}
+	}
+	return running, nil
+}
+
+// pickCurrentLecture wählt bei überlappenden Vorlesungen eine aus: eine bereits angezeigte
+// Vorlesung bleibt aktiv, ansonsten gewinnt die früher beginnende bzw. längere Vorlesung
+func pickCurrentLecture(running []LectureEvent, state *ActiveLectureState) *LectureEvent {
+	if len(running) == 0 {
+		return nil
+	}
+
+	if state != nil && state.Mode == LectureRunning {
+		for _, lecture := range running {
+			if lecture.Name == state.LectureName && lecture.Start.Equal(state.LectureStart) {
+				return &lecture
+			}
+		}
+	}
+
+	sort.SliceStable(running, func(i, j int) bool {
+		if !running[i].Start.Equal(running[j].Start) {
+			return running[i].Start.Before(running[j].Start)
+		}
+		return running[i].End.After(running[j].End)
+	})
+	return &running[0]
+}
+
+// blockFields liefert die Embed-Felder für den Block und parallel laufende Vorlesungen
+func blockFields(block *lectureBlock, lecture *LectureEvent) []*discordgo.MessageEmbedField {
+	if block == nil {
+		return nil
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Block",
+			Value:  fmt.Sprintf("%s (%s - %s)", slotLabel(block.Slot), block.Start.Format("15:04"), block.End.Format("15:04")),
+			Inline: true,
+		},
+	}
+
+	var parallel []string
+	for _, other := range block.Lectures {
+		if other.Name == lecture.Name && other.Start.Equal(lecture.Start) {
+			continue
+		}
+		if other.Start.Before(lecture.End) && other.End.After(lecture.Start) {
+			parallel = append(parallel, fmt.Sprintf("%s (%s - %s)", other.Name, other.Start.Format("15:04"), other.End.Format("15:04")))
+		}
+	}
+	if len(parallel) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Parallel",
+			Value:  strings.Join(parallel, "\n"),
+			Inline: false,
+		})
+	}
+	return fields
+}
+
+func saveBlockGap(db *sql.DB, guildID string, minutes int) error {
+	_, err := db.Exec("UPDATE timer_config SET block_gap_minutes = $1, updated_at = CURRENT_TIMESTAMP WHERE guild_id = $2", minutes, guildID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Blockpause: %w", err)
+	}
+	return nil
+}
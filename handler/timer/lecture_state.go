@@ -0,0 +1,56 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// primaryLectureKey kennzeichnet das Haupt-Embed, parallele Vorlesungen nutzen ihren lectureKey
+const primaryLectureKey = ""
+
+func loadLectureStates(db *sql.DB, guildID string) (map[string]*ActiveLectureState, error) {
+	states := make(map[string]*ActiveLectureState)
+
+	rows, err := db.Query(`SELECT lecture_key, channel_id, message_id, mode, lecture_slot, lecture_date, lecture_name, lecture_start, lecture_end
+		FROM lecture_state WHERE guild_id = $1`, guildID)
+	if err != nil {
+		return states, fmt.Errorf("fehler beim Laden des Vorlesungszustands: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var state ActiveLectureState
+		var key, slot, mode string
+		err := rows.Scan(&key, &state.ChannelID, &state.MessageID, &mode, &slot, &state.Date, &state.LectureName, &state.LectureStart, &state.LectureEnd)
+		if err != nil {
+			return states, fmt.Errorf("fehler beim Lesen des Vorlesungszustands: %w", err)
+		}
+
+		state.Mode = LectureMode(mode)
+		state.LectureSlot = LectureSlot(slot)
+		states[key] = &state
+	}
+	return states, rows.Err()
+}
+
+func saveLectureState(db *sql.DB, guildID, key string, state *ActiveLectureState) error {
+	_, err := db.Exec(`INSERT INTO lecture_state (guild_id, lecture_key, channel_id, message_id, mode, lecture_slot, lecture_date, lecture_name, lecture_start, lecture_end)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (guild_id, lecture_key) DO UPDATE
+		SET channel_id = EXCLUDED.channel_id, message_id = EXCLUDED.message_id, mode = EXCLUDED.mode, lecture_slot = EXCLUDED.lecture_slot,
+			lecture_date = EXCLUDED.lecture_date, lecture_name = EXCLUDED.lecture_name,
+			lecture_start = EXCLUDED.lecture_start, lecture_end = EXCLUDED.lecture_end, updated_at = CURRENT_TIMESTAMP`,
+		guildID, key, state.ChannelID, state.MessageID, string(state.Mode), string(state.LectureSlot), state.Date, state.LectureName, state.LectureStart, state.LectureEnd)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern des Vorlesungszustands: %w", err)
+	}
+	return nil
+}
+
+func deleteLectureState(db *sql.DB, guildID, key string) error {
+	_, err := db.Exec("DELETE FROM lecture_state WHERE guild_id = $1 AND lecture_key = $2", guildID, key)
+	if err != nil {
+		return fmt.Errorf("fehler beim Löschen des Vorlesungszustands: %w", err)
+	}
+	return nil
+}
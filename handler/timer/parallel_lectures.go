@@ -0,0 +1,95 @@
+package timer
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// updateParallelLectures sendet bzw. aktualisiert die Embeds aller laufenden Vorlesungen
+// neben der Hauptvorlesung und schließt Embeds beendeter Vorlesungen ab
+func (t *lectureTracker) updateParallelLectures(s *discordgo.Session, running []LectureEvent, primary *LectureEvent) {
+	active := make(map[string]bool)
+
+	for _, lecture := range running {
+		if primary != nil && lectureKey(lecture) == lectureKey(*primary) {
+			continue
+		}
+
+		key := lectureKey(lecture)
+		active[key] = true
+		view := t.buildLectureEmbed(&lecture)
+
+		if _, exists := t.parallelLectures[key]; !exists {
+			if state := t.sendLectureEmbed(s, &lecture, view); state != nil {
+				t.setParallelLecture(key, state)
+			}
+			continue
+		}
+		t.editParallelEmbed(s, key, view.Embed, view.Files, view.Components...)
+	}
+
+	for key, state := range t.parallelLectures {
+		if active[key] {
+			continue
+		}
+
+		view := t.buildLectureEmbed(&LectureEvent{
+			Name:  state.LectureName,
+			Start: state.LectureStart,
+			End:   state.LectureEnd,
+		})
+		t.editParallelEmbed(s, key, view.Embed, view.Files)
+		t.setParallelLecture(key, nil)
+	}
+}
+
+// promoteParallelLecture übernimmt das Embed einer parallelen Vorlesung als Haupt-Embed,
+// wenn die bisherige Hauptvorlesung vor ihr endet
+func (t *lectureTracker) promoteParallelLecture(s *discordgo.Session, primary *LectureEvent) {
+	if primary == nil {
+		return
+	}
+
+	key := lectureKey(*primary)
+	state, exists := t.parallelLectures[key]
+	if !exists {
+		return
+	}
+
+	t.finishCurrentLecture(s)
+	if t.currentLecture != nil && t.currentLecture.Mode == BreakRunning {
+		t.finishBreak(s, primary)
+	}
+
+	t.setParallelLecture(key, nil)
+	t.setCurrentLecture(state)
+}
+
+// setParallelLecture setzt den Zustand einer parallelen Vorlesung und speichert ihn in der Datenbank
+func (t *lectureTracker) setParallelLecture(key string, state *ActiveLectureState) {
+	var err error
+	if state == nil {
+		delete(t.parallelLectures, key)
+		err = deleteLectureState(t.db, t.config.GuildID, key)
+	} else {
+		t.parallelLectures[key] = state
+		err = saveLectureState(t.db, t.config.GuildID, key, state)
+	}
+	t.publishAttendance()
+	if err != nil {
+		fmt.Println("Fehler beim Speichern des Vorlesungszustands:", err)
+	}
+}
+
+func (t *lectureTracker) editParallelEmbed(s *discordgo.Session, key string, embed *discordgo.MessageEmbed, files []*discordgo.File, components ...discordgo.MessageComponent) {
+	state := t.parallelLectures[key]
+	err := editEmbedMessage(s, state.ChannelID, state.MessageID, embed, files, components...)
+	if err != nil {
+		fmt.Println("Fehler beim Bearbeiten der Nachricht:", err)
+		// Nachricht wurde gelöscht, beim nächsten Durchlauf neu senden
+		if isUnknownMessage(err) {
+			t.setParallelLecture(key, nil)
+		}
+	}
+}
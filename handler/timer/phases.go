@@ -0,0 +1,85 @@
+package timer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Phasen wechseln sich im dualen Studium ab, Prüfungsphasen und Ferien können innerhalb anderer Phasen liegen
+var phaseKinds = []string{periodTheory, periodPractice, periodExams, periodHoliday}
+
+var phaseStyles = map[string]struct {
+	Icon  string
+	Color int
+}{
+	periodTheory:   {"📘", 0x00ccff},
+	periodPractice: {"🏢", 0xff9900},
+	periodExams:    {"📝", 0xff3333},
+	periodHoliday:  {"🏖️", 0x00ff00},
+}
+
+func isPhase(period studyPeriod) bool {
+	for _, kind := range phaseKinds {
+		if period.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// currentPhase liefert die aktuelle Phase, bei Überschneidungen die kürzere (z.B. Prüfungsphase in der Theoriephase)
+func currentPhase(periods []studyPeriod, now time.Time) *studyPeriod {
+	var current *studyPeriod
+	for i, period := range periods {
+		if !isPhase(period) || !period.Contains(now) {
+			continue
+		}
+		if current == nil || period.End.Sub(period.Start) < current.End.Sub(current.Start) {
+			current = &periods[i]
+		}
+	}
+	return current
+}
+
+// nextPhase liefert die als Nächstes beginnende Phase
+func nextPhase(periods []studyPeriod, now time.Time) *studyPeriod {
+	var next *studyPeriod
+	for i, period := range periods {
+		if !isPhase(period) || !period.Start.After(now) {
+			continue
+		}
+		if next == nil || period.Start.Before(next.Start) {
+			next = &periods[i]
+		}
+	}
+	return next
+}
+
+func phaseTitle(period studyPeriod) string {
+	return fmt.Sprintf("%s %s: %s", phaseStyles[period.Kind].Icon, periodLabels[period.Kind], period.Name)
+}
+
+// phaseEmbed zeigt den Fortschritt der aktuellen Phase und den Countdown bis zur nächsten Phase
+func phaseEmbed(periods []studyPeriod, now time.Time) (*discordgo.MessageEmbed, []*discordgo.File) {
+	var embed *discordgo.MessageEmbed
+	var files []*discordgo.File
+	if current := currentPhase(periods, now); current != nil {
+		embed, files = progressEmbed(phaseTitle(*current), fmt.Sprintf("%s vom %s", periodLabels[current.Kind], formatPeriodRange(*current)), *current)
+		embed.Color = phaseStyles[current.Kind].Color
+	} else {
+		embed = placeholderEmbed("Aktuelle Phase", "Aktuell ist keine Phase eingetragen.")
+	}
+
+	if next := nextPhase(periods, now); next != nil {
+		days := int(next.Start.Sub(now).Hours() / 24)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: "Nächste Phase",
+			Value: fmt.Sprintf("%s beginnt am %s (<t:%d:R>), noch %d Tage",
+				phaseTitle(*next), next.Start.Format("02.01.2006"), next.Start.Unix(), days),
+			Inline: false,
+		})
+	}
+	return embed, files
+}
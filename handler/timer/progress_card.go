@@ -0,0 +1,197 @@
+package timer
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	cardWidth    = 600
+	cardHeight   = 110
+	cardPadding  = 24
+	cardFileName = "fortschritt.png"
+
+	// Skalierung der 5x7-Pixelschrift
+	cardTextScale = 4
+)
+
+var (
+	cardBackground = color.RGBA{0x2b, 0x2d, 0x31, 0xff}
+	cardTrack      = color.RGBA{0x40, 0x42, 0x49, 0xff}
+	cardText       = color.RGBA{0xf2, 0xf3, 0xf5, 0xff}
+	cardMuted      = color.RGBA{0x94, 0x9b, 0xa4, 0xff}
+)
+
+// cardGlyphs ist eine minimale 5x7-Pixelschrift für Prozentwerte und Zeiten,
+// damit keine Schriftdateien oder externen Pakete benötigt werden
+var cardGlyphs = map[rune][7]uint8{
+	'0': {0x0e, 0x11, 0x13, 0x15, 0x19, 0x11, 0x0e},
+	'1': {0x04, 0x0c, 0x04, 0x04, 0x04, 0x04, 0x0e},
+	'2': {0x0e, 0x11, 0x01, 0x02, 0x04, 0x08, 0x1f},
+	'3': {0x1f, 0x02, 0x04, 0x02, 0x01, 0x11, 0x0e},
+	'4': {0x02, 0x06, 0x0a, 0x12, 0x1f, 0x02, 0x02},
+	'5': {0x1f, 0x10, 0x1e, 0x01, 0x01, 0x11, 0x0e},
+	'6': {0x06, 0x08, 0x10, 0x1e, 0x11, 0x11, 0x0e},
+	'7': {0x1f, 0x01, 0x02, 0x04, 0x08, 0x08, 0x08},
+	'8': {0x0e, 0x11, 0x11, 0x0e, 0x11, 0x11, 0x0e},
+	'9': {0x0e, 0x11, 0x11, 0x0f, 0x01, 0x02, 0x0c},
+	'.': {0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x0c},
+	'%': {0x18, 0x19, 0x02, 0x04, 0x08, 0x13, 0x03},
+	':': {0x00, 0x0c, 0x0c, 0x00, 0x0c, 0x0c, 0x00},
+	' ': {},
+}
+
+// renderProgressCard zeichnet eine Fortschrittskarte mit Prozentwert, Restzeit und Balken als PNG
+func renderProgressCard(percentage float64, remaining string, accent int) ([]byte, error) {
+	if percentage < 0 {
+		percentage = 0
+	}
+	if percentage > 100 {
+		percentage = 100
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, cardWidth, cardHeight))
+	fillRoundedRect(img, img.Bounds(), 16, cardBackground)
+
+	accentColor := color.RGBA{uint8(accent >> 16), uint8(accent >> 8), uint8(accent), 0xff}
+
+	// Prozentwert links, Restzeit rechts
+	if err := drawCardText(img, fmt.Sprintf("%.1f%%", percentage), cardPadding, cardPadding, cardTextScale, cardText); err != nil {
+		return nil, err
+	}
+	remainingWidth := textWidth(remaining, cardTextScale-1)
+	if err := drawCardText(img, remaining, cardWidth-cardPadding-remainingWidth, cardPadding+cardTextScale, cardTextScale-1, cardMuted); err != nil {
+		return nil, err
+	}
+
+	// Balken mit Markierungen bei 25, 50 und 75 %
+	bar := image.Rect(cardPadding, cardHeight-cardPadding-24, cardWidth-cardPadding, cardHeight-cardPadding)
+	fillRoundedRect(img, bar, 12, cardTrack)
+	filled := bar
+	filled.Max.X = bar.Min.X + int(float64(bar.Dx())*percentage/100)
+	if filled.Dx() > 0 {
+		fillRoundedRect(img, filled, 12, accentColor)
+	}
+	for _, mark := range []float64{25, 50, 75} {
+		x := bar.Min.X + int(float64(bar.Dx())*mark/100)
+		for y := bar.Min.Y + 6; y < bar.Max.Y-6; y++ {
+			img.Set(x, y, cardBackground)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("fehler beim Kodieren der Fortschrittskarte: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// withProgressCard hängt eine gerenderte Fortschrittskarte an das Embed an und ersetzt damit
+// den Text-Fortschrittsbalken. Schlägt das Rendern fehl, bleibt der Textbalken erhalten.
+func withProgressCard(embed *discordgo.MessageEmbed, percentage float64, remainingMinutes int) []*discordgo.File {
+	card, err := renderProgressCard(percentage, formatTimeFromMinutes(remainingMinutes), embed.Color)
+	if err != nil {
+		fmt.Println("Fortschrittskarte konnte nicht erstellt werden, nutze Textbalken:", err)
+		return nil
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, field := range embed.Fields {
+		if field.Name != "Fortschrittsbalken" {
+			fields = append(fields, field)
+		}
+	}
+	embed.Fields = fields
+	embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + cardFileName}
+
+	return []*discordgo.File{{
+		Name:        cardFileName,
+		ContentType: "image/png",
+		Reader:      bytes.NewReader(card),
+	}}
+}
+
+func drawCardText(img *image.RGBA, text string, x, y, scale int, c color.Color) error {
+	for _, r := range text {
+		glyph, ok := cardGlyphs[r]
+		if !ok {
+			return fmt.Errorf("zeichen %q wird von der Fortschrittskarte nicht unterstützt", r)
+		}
+		for row, bits := range glyph {
+			for col := 0; col < 5; col++ {
+				if bits&(1<<(4-col)) == 0 {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.Set(x+col*scale+dx, y+row*scale+dy, c)
+					}
+				}
+			}
+		}
+		x += 6 * scale
+	}
+	return nil
+}
+
+func textWidth(text string, scale int) int {
+	n := len([]rune(text))
+	if n == 0 {
+		return 0
+	}
+	return n*6*scale - scale
+}
+
+// fillRoundedRect füllt ein Rechteck mit abgerundeten Ecken
+func fillRoundedRect(img *image.RGBA, r image.Rectangle, radius int, c color.Color) {
+	if radius*2 > r.Dy() {
+		radius = r.Dy() / 2
+	}
+	if radius*2 > r.Dx() {
+		radius = r.Dx() / 2
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			// Abstand zur nächsten Eckenmitte, nur in den Ecken relevant
+			cx, cy := x, y
+			if x < r.Min.X+radius {
+				cx = r.Min.X + radius
+			} else if x >= r.Max.X-radius {
+				cx = r.Max.X - radius - 1
+			}
+			if y < r.Min.Y+radius {
+				cy = r.Min.Y + radius
+			} else if y >= r.Max.Y-radius {
+				cy = r.Max.Y - radius - 1
+			}
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy <= radius*radius {
+				img.Set(x, y, c)
+			}
+		}
+	}
+}
+
+// editEmbedMessage ersetzt Embed, Anhänge und Buttons einer Nachricht. Alte Fortschrittskarten
+// werden dabei entfernt, ohne components werden auch vorhandene Buttons entfernt.
+func editEmbedMessage(s *discordgo.Session, channelID, messageID string, embed *discordgo.MessageEmbed, files []*discordgo.File, components ...discordgo.MessageComponent) error {
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+
+	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:          messageID,
+		Channel:     channelID,
+		Embeds:      &[]*discordgo.MessageEmbed{embed},
+		Components:  &components,
+		Files:       files,
+		Attachments: &[]*discordgo.MessageAttachment{},
+	})
+	return err
+}
@@ -0,0 +1,80 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Arten von Nachrichten im Fortschrittskanal, die dauerhaft bearbeitet statt neu gesendet werden
+const (
+	progressMessageStudy    = "study"
+	progressMessageSemester = "semester"
+	progressMessagePhase    = "phase"
+	progressMessageExams    = "exams"
+)
+
+// publishProgressEmbed bearbeitet die gespeicherte Nachricht einer Fortschrittsanzeige oder sendet
+// sie neu, falls sie noch nicht existiert, gelöscht wurde oder der Kanal gewechselt hat
+func publishProgressEmbed(s *discordgo.Session, db *sql.DB, cfg GuildConfig, kind string, embed *discordgo.MessageEmbed, files []*discordgo.File, pin bool) {
+	channelID, messageID, err := loadProgressMessage(db, cfg.GuildID, kind)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Fortschrittsnachricht:", err)
+		return
+	}
+
+	if messageID != "" && channelID == cfg.ProgressChannelID {
+		err := editEmbedMessage(s, channelID, messageID, embed, files)
+		if err == nil {
+			return
+		}
+		fmt.Printf("Fehler beim Bearbeiten der Fortschrittsnachricht (%s): %v\n", kind, err)
+		if !isUnknownMessage(err) {
+			return
+		}
+	}
+
+	msg, err := s.ChannelMessageSendComplex(cfg.ProgressChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Files:  files,
+	})
+	if err != nil {
+		fmt.Printf("Fehler beim Senden der Fortschrittsnachricht (%s): %v\n", kind, err)
+		return
+	}
+	if pin {
+		if err := s.ChannelMessagePin(cfg.ProgressChannelID, msg.ID); err != nil {
+			fmt.Printf("Fehler beim Anheften der Fortschrittsnachricht (%s): %v\n", kind, err)
+		}
+	}
+	if err := saveProgressMessage(db, cfg.GuildID, kind, cfg.ProgressChannelID, msg.ID); err != nil {
+		fmt.Println("Fehler beim Speichern der Fortschrittsnachricht:", err)
+	}
+}
+
+// loadProgressMessage liefert Kanal und Nachricht eines Fortschritts-Embeds oder leere Werte
+func loadProgressMessage(db *sql.DB, guildID, kind string) (string, string, error) {
+	var channelID, messageID string
+	err := db.QueryRow("SELECT channel_id, message_id FROM progress_messages WHERE guild_id = $1 AND kind = $2", guildID, kind).
+		Scan(&channelID, &messageID)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("fehler beim Laden der Fortschrittsnachricht: %w", err)
+	}
+	return channelID, messageID, nil
+}
+
+func saveProgressMessage(db *sql.DB, guildID, kind, channelID, messageID string) error {
+	_, err := db.Exec(`INSERT INTO progress_messages (guild_id, kind, channel_id, message_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (guild_id, kind) DO UPDATE
+		SET channel_id = EXCLUDED.channel_id, message_id = EXCLUDED.message_id, updated_at = CURRENT_TIMESTAMP`,
+		guildID, kind, channelID, messageID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Fortschrittsnachricht: %w", err)
+	}
+	return nil
+}
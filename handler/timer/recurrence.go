@@ -0,0 +1,544 @@
+package timer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arran4/golang-ical"
+)
+
+// Wie weit wiederkehrende Termine ohne COUNT/UNTIL in die Zukunft aufgelöst werden
+const recurrenceHorizon = 400 * 24 * time.Hour
+
+// Schutz gegen fehlerhafte Regeln, die nie enden. Gezählt werden nur Termine
+// innerhalb des angefragten Zeitraums.
+const maxOccurrencesPerEvent = 2000
+
+// Obergrenze für durchlaufene Tage/Wochen/Monate/Jahre einer Regel
+const maxRecurrencePeriods = 100000
+
+const icalUIDTimeFormat = "20060102T150405Z"
+
+// occurrence ist ein einzelner Termin eines (ggf. wiederkehrenden) Events
+type occurrence struct {
+	Event *ics.VEvent
+	UID   string
+	Start time.Time
+	End   time.Time
+}
+
+type recurrenceRule struct {
+	Freq     string
+	Interval int
+	Count    int
+	Until    time.Time
+	ByDay    []weekdayNum
+}
+
+// weekdayNum ist ein BYDAY-Eintrag wie "MO", "1MO" oder "-1FR". Ordinal 0 steht
+// für jeden passenden Wochentag im Monat bzw. Jahr.
+type weekdayNum struct {
+	Weekday time.Weekday
+	Ordinal int
+}
+
+// icalTime ist ein Zeitpunkt aus EXDATE/RDATE/RECURRENCE-ID, ggf. nur als Datum
+type icalTime struct {
+	Time     time.Time
+	DateOnly bool
+}
+
+// expandCalendar löst RRULE, RDATE, EXDATE und RECURRENCE-ID auf und liefert alle
+// einzelnen Termine, die vor until beginnen. Serientermine vor from werden
+// übersprungen, damit lange laufende Serien nicht an maxOccurrencesPerEvent scheitern.
+func expandCalendar(cal *ics.Calendar, from, until time.Time) []occurrence {
+	// Überschriebene Einzeltermine (RECURRENCE-ID) nach UID sammeln
+	overrides := make(map[string]map[int64]*ics.VEvent)
+	var masters []*ics.VEvent
+
+	for _, event := range cal.Events() {
+		recurrenceID := event.GetProperty(ics.ComponentPropertyRecurrenceId)
+		if recurrenceID == nil {
+			masters = append(masters, event)
+			continue
+		}
+
+		id, err := parseICalTime(recurrenceID.Value, recurrenceID.ICalParameters)
+		if err != nil {
+			continue
+		}
+		uid := propertyValue(event, ics.ComponentPropertyUniqueId)
+		if overrides[uid] == nil {
+			overrides[uid] = make(map[int64]*ics.VEvent)
+		}
+		overrides[uid][id.Time.Unix()] = event
+	}
+
+	var occurrences []occurrence
+	usedOverrides := make(map[*ics.VEvent]bool)
+
+	for _, event := range masters {
+		start, err := event.GetStartAt()
+		if err != nil {
+			continue
+		}
+		end, err := event.GetEndAt()
+		if err != nil {
+			end = start
+		}
+		duration := end.Sub(start)
+		uid := propertyValue(event, ics.ComponentPropertyUniqueId)
+
+		starts, recurring := recurrenceStarts(event, start, from, until)
+		for _, occStart := range starts {
+			occ := occurrence{Event: event, UID: uid, Start: occStart, End: occStart.Add(duration)}
+			if recurring {
+				occ.UID = uid + "/" + occStart.UTC().Format(icalUIDTimeFormat)
+			}
+
+			if override, exists := overrides[uid][occStart.Unix()]; exists {
+				usedOverrides[override] = true
+				overrideStart, err := override.GetStartAt()
+				if err != nil {
+					continue
+				}
+				overrideEnd, err := override.GetEndAt()
+				if err != nil {
+					overrideEnd = overrideStart.Add(duration)
+				}
+				occ.Event = override
+				occ.Start = overrideStart
+				occ.End = overrideEnd
+			}
+
+			occurrences = append(occurrences, occ)
+		}
+	}
+
+	// Überschreibungen ohne passenden Serientermin trotzdem übernehmen
+	for uid, byStart := range overrides {
+		for originalStart, event := range byStart {
+			if usedOverrides[event] {
+				continue
+			}
+			start, err := event.GetStartAt()
+			if err != nil {
+				continue
+			}
+			end, err := event.GetEndAt()
+			if err != nil {
+				end = start
+			}
+			occurrences = append(occurrences, occurrence{
+				Event: event,
+				UID:   uid + "/" + time.Unix(originalStart, 0).UTC().Format(icalUIDTimeFormat),
+				Start: start,
+				End:   end,
+			})
+		}
+	}
+
+	return occurrences
+}
+
+// recurrenceStarts liefert alle Startzeitpunkte eines Events. recurring ist false,
+// wenn das Event weder RRULE noch RDATE besitzt.
+func recurrenceStarts(event *ics.VEvent, start, from, until time.Time) (starts []time.Time, recurring bool) {
+	rrules := event.GetProperties(ics.ComponentPropertyRrule)
+	rdates := event.GetProperties(ics.ComponentPropertyRdate)
+	if len(rrules) == 0 && len(rdates) == 0 {
+		return []time.Time{start}, false
+	}
+
+	seen := make(map[int64]bool)
+	add := func(t time.Time) {
+		if !seen[t.Unix()] {
+			seen[t.Unix()] = true
+			starts = append(starts, t)
+		}
+	}
+
+	if len(rrules) == 0 {
+		add(start)
+	}
+	for _, prop := range rrules {
+		rule, err := parseRRule(prop.Value, start.Location())
+		if err != nil {
+			fmt.Printf("Warnung: RRULE von %q nicht unterstützt: %v\n", propertyValue(event, ics.ComponentPropertySummary), err)
+			add(start)
+			continue
+		}
+		for _, t := range rule.occurrences(start, from, until) {
+			add(t)
+		}
+	}
+
+	for _, prop := range rdates {
+		for _, value := range strings.Split(prop.Value, ",") {
+			rdate, err := parseICalTime(value, prop.ICalParameters)
+			if err != nil {
+				continue
+			}
+			t := rdate.Time
+			if rdate.DateOnly {
+				t = time.Date(t.Year(), t.Month(), t.Day(), start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+			}
+			if t.Before(until) {
+				add(t)
+			}
+		}
+	}
+
+	// Ausnahmen (EXDATE) entfernen
+	var exdates []icalTime
+	for _, prop := range event.GetProperties(ics.ComponentPropertyExdate) {
+		for _, value := range strings.Split(prop.Value, ",") {
+			if exdate, err := parseICalTime(value, prop.ICalParameters); err == nil {
+				exdates = append(exdates, exdate)
+			}
+		}
+	}
+
+	filtered := starts[:0]
+	for _, t := range starts {
+		if !isExcluded(t, exdates) {
+			filtered = append(filtered, t)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].Before(filtered[j])
+	})
+	return filtered, true
+}
+
+func isExcluded(t time.Time, exdates []icalTime) bool {
+	for _, exdate := range exdates {
+		if exdate.DateOnly {
+			local := t.In(exdate.Time.Location())
+			if local.Year() == exdate.Time.Year() && local.YearDay() == exdate.Time.YearDay() {
+				return true
+			}
+		} else if exdate.Time.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseRRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	rule := &recurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		key, val, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("ungültiges INTERVAL %q", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("ungültiges COUNT %q", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseICalTime(val, map[string][]string{"TZID": {loc.String()}})
+			if err != nil {
+				return nil, fmt.Errorf("ungültiges UNTIL %q", val)
+			}
+			rule.Until = until.Time
+			if until.DateOnly {
+				// Ein Datum schließt den ganzen Tag mit ein
+				rule.Until = until.Time.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				weekday, ok := parseWeekday(day)
+				if !ok {
+					return nil, fmt.Errorf("ungültiges BYDAY %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "WKST":
+			// Wochen werden immer ab Montag gezählt
+			if !strings.EqualFold(val, "MO") {
+				return nil, fmt.Errorf("WKST=%s wird nicht unterstützt", val)
+			}
+		default:
+			// Unbekannte Teile (BYMONTHDAY, BYSETPOS, BYMONTH, ...) würden die Serie
+			// verfälschen, der Aufrufer nimmt dann nur DTSTART
+			return nil, fmt.Errorf("%s wird nicht unterstützt", strings.ToUpper(key))
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("FREQ %q wird nicht unterstützt", rule.Freq)
+	}
+
+	if rule.Freq == "DAILY" || rule.Freq == "WEEKLY" {
+		for _, day := range rule.ByDay {
+			if day.Ordinal != 0 {
+				return nil, fmt.Errorf("BYDAY mit Ordinalzahl bei FREQ=%s", rule.Freq)
+			}
+		}
+	}
+
+	return rule, nil
+}
+
+// parseWeekday wertet BYDAY-Werte wie "MO", "1MO" oder "-1FR" aus
+func parseWeekday(value string) (weekdayNum, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return weekdayNum{}, false
+	}
+
+	var day weekdayNum
+	if prefix := value[:len(value)-2]; prefix != "" {
+		ordinal, err := strconv.Atoi(prefix)
+		if err != nil || ordinal == 0 || ordinal < -53 || ordinal > 53 {
+			return weekdayNum{}, false
+		}
+		day.Ordinal = ordinal
+	}
+
+	switch value[len(value)-2:] {
+	case "SU":
+		day.Weekday = time.Sunday
+	case "MO":
+		day.Weekday = time.Monday
+	case "TU":
+		day.Weekday = time.Tuesday
+	case "WE":
+		day.Weekday = time.Wednesday
+	case "TH":
+		day.Weekday = time.Thursday
+	case "FR":
+		day.Weekday = time.Friday
+	case "SA":
+		day.Weekday = time.Saturday
+	default:
+		return weekdayNum{}, false
+	}
+	return day, true
+}
+
+// occurrences liefert alle Starts der Regel ab dtstart, die vor limit beginnen.
+// Termine vor from zählen nur für COUNT und werden nicht zurückgegeben.
+func (r *recurrenceRule) occurrences(dtstart, from, limit time.Time) []time.Time {
+	var result []time.Time
+	emitted := 0
+
+	// Ohne COUNT muss nicht ab DTSTART gezählt werden, dann direkt zum Zeitraum springen
+	first := 0
+	if r.Count == 0 {
+		first = r.firstPeriod(dtstart, from)
+	}
+
+	for period := first; period < first+maxRecurrencePeriods; period++ {
+		periodStart, candidates := r.periodCandidates(dtstart, period)
+		if !periodStart.Before(limit) {
+			break
+		}
+
+		for _, t := range candidates {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return result
+			}
+			if !t.Before(limit) || (r.Count > 0 && emitted >= r.Count) {
+				return result
+			}
+			emitted++
+
+			if t.Before(from) {
+				continue
+			}
+			if len(result) >= maxOccurrencesPerEvent {
+				return result
+			}
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// firstPeriod schätzt den Zeitraum (Tag/Woche/Monat/Jahr ab DTSTART), in dem from
+// liegt. Lieber einen Zeitraum zu früh, Termine vor from werden ohnehin übersprungen.
+func (r *recurrenceRule) firstPeriod(dtstart, from time.Time) int {
+	if !from.After(dtstart) {
+		return 0
+	}
+
+	var periods int
+	switch r.Freq {
+	case "DAILY":
+		periods = int(from.Sub(dtstart).Hours()/24) / r.Interval
+	case "WEEKLY":
+		periods = int(from.Sub(dtstart).Hours()/24) / 7 / r.Interval
+	case "MONTHLY":
+		periods = ((from.Year()-dtstart.Year())*12 + int(from.Month()-dtstart.Month())) / r.Interval
+	case "YEARLY":
+		periods = (from.Year() - dtstart.Year()) / r.Interval
+	}
+	return max(periods-1, 0)
+}
+
+// periodCandidates liefert den Beginn des n-ten Zeitraums der Regel und alle darin
+// liegenden Termine in aufsteigender Reihenfolge
+func (r *recurrenceRule) periodCandidates(dtstart time.Time, n int) (time.Time, []time.Time) {
+	at := func(day time.Time) time.Time {
+		// Wanduhrzeit beibehalten, damit Sommer-/Winterzeit korrekt ist
+		return time.Date(day.Year(), day.Month(), day.Day(), dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, dtstart.Location())
+	}
+
+	var candidates []time.Time
+	switch r.Freq {
+	case "DAILY":
+		day := date(dtstart.Year(), dtstart.Month(), dtstart.Day()+n*r.Interval)
+		// BYDAY schränkt tägliche Serien auf bestimmte Wochentage ein
+		if len(r.ByDay) == 0 || matchesWeekday(day.Weekday(), r.ByDay) {
+			candidates = append(candidates, at(day))
+		}
+		return day, candidates
+
+	case "WEEKLY":
+		// Wochen beginnen am Montag (WKST=MO)
+		weekStart := date(dtstart.Year(), dtstart.Month(), dtstart.Day()-(int(dtstart.Weekday())+6)%7+n*7*r.Interval)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []weekdayNum{{Weekday: dtstart.Weekday()}}
+		}
+		offsets := make([]int, 0, len(days))
+		for _, day := range days {
+			offsets = append(offsets, (int(day.Weekday)+6)%7)
+		}
+		sort.Ints(offsets)
+		for _, offset := range offsets {
+			candidates = append(candidates, at(weekStart.AddDate(0, 0, offset)))
+		}
+		return weekStart, candidates
+
+	case "MONTHLY":
+		monthStart := date(dtstart.Year(), dtstart.Month()+time.Month(n*r.Interval), 1)
+		if len(r.ByDay) == 0 {
+			// Monate ohne diesen Tag (z.B. 31.) überspringen
+			day := date(monthStart.Year(), monthStart.Month(), dtstart.Day())
+			if day.Month() == monthStart.Month() {
+				candidates = append(candidates, at(day))
+			}
+			return monthStart, candidates
+		}
+		for _, day := range weekdaysInRange(monthStart, monthStart.AddDate(0, 1, 0), r.ByDay) {
+			candidates = append(candidates, at(day))
+		}
+		return monthStart, candidates
+
+	default: // YEARLY
+		yearStart := date(dtstart.Year()+n*r.Interval, time.January, 1)
+		if len(r.ByDay) == 0 {
+			// 29. Februar nur in Schaltjahren
+			day := date(yearStart.Year(), dtstart.Month(), dtstart.Day())
+			if day.Day() == dtstart.Day() {
+				candidates = append(candidates, at(day))
+			}
+			return yearStart, candidates
+		}
+		for _, day := range weekdaysInRange(yearStart, yearStart.AddDate(1, 0, 0), r.ByDay) {
+			candidates = append(candidates, at(day))
+		}
+		return yearStart, candidates
+	}
+}
+
+func matchesWeekday(weekday time.Weekday, days []weekdayNum) bool {
+	for _, day := range days {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdaysInRange liefert alle Tage in [start, end), die zu einem BYDAY-Eintrag passen.
+// Ordinalzahlen zählen innerhalb des Zeitraums, "-1FR" ist also der letzte Freitag.
+func weekdaysInRange(start, end time.Time, days []weekdayNum) []time.Time {
+	byWeekday := make(map[time.Weekday][]time.Time)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		byWeekday[day.Weekday()] = append(byWeekday[day.Weekday()], day)
+	}
+
+	seen := make(map[time.Time]bool)
+	var result []time.Time
+	add := func(day time.Time) {
+		if !seen[day] {
+			seen[day] = true
+			result = append(result, day)
+		}
+	}
+
+	for _, day := range days {
+		matching := byWeekday[day.Weekday]
+		switch {
+		case day.Ordinal == 0:
+			for _, d := range matching {
+				add(d)
+			}
+		case day.Ordinal > 0 && day.Ordinal <= len(matching):
+			add(matching[day.Ordinal-1])
+		case day.Ordinal < 0 && -day.Ordinal <= len(matching):
+			add(matching[len(matching)+day.Ordinal])
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Before(result[j])
+	})
+	return result
+}
+
+// parseICalTime wertet DATE und DATE-TIME Werte inklusive TZID-Parameter aus
+func parseICalTime(value string, params map[string][]string) (icalTime, error) {
+	value = strings.TrimSpace(value)
+
+	loc := time.Local
+	if tzid, ok := params["TZID"]; ok && len(tzid) > 0 {
+		if l, err := time.LoadLocation(strings.Trim(tzid[0], "\"")); err == nil {
+			loc = l
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse(icalUIDTimeFormat, value)
+		return icalTime{Time: t}, err
+	case len(value) == len("20060102T150405"):
+		t, err := time.ParseInLocation("20060102T150405", value, loc)
+		return icalTime{Time: t}, err
+	case len(value) == len("20060102"):
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return icalTime{Time: t, DateOnly: true}, err
+	}
+
+	return icalTime{}, fmt.Errorf("unbekanntes Zeitformat %q", value)
+}
@@ -0,0 +1,173 @@
+package timer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arran4/golang-ical"
+)
+
+func parseTestCalendar(t *testing.T, events ...string) *ics.Calendar {
+	t.Helper()
+
+	body := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:test\r\n" +
+		strings.Join(events, "") +
+		"END:VCALENDAR\r\n"
+	cal, err := ics.ParseCalendar(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Kalender nicht lesbar: %v", err)
+	}
+	return cal
+}
+
+func testEvent(lines ...string) string {
+	return "BEGIN:VEVENT\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VEVENT\r\n"
+}
+
+func TestExpandCalendar(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		events []string
+		want   []string // Startzeiten im Format icalUIDTimeFormat
+	}{
+		{
+			name: "einzelner Termin",
+			events: []string{testEvent(
+				"UID:single",
+				"DTSTART:20240110T080000Z",
+				"DTEND:20240110T093000Z",
+			)},
+			want: []string{"20240110T080000Z"},
+		},
+		{
+			name: "wöchentlich mit COUNT",
+			events: []string{testEvent(
+				"UID:weekly",
+				"DTSTART:20240108T080000Z",
+				"DTEND:20240108T093000Z",
+				"RRULE:FREQ=WEEKLY;COUNT=3",
+			)},
+			want: []string{"20240108T080000Z", "20240115T080000Z", "20240122T080000Z"},
+		},
+		{
+			name: "wöchentlich mit UNTIL und EXDATE",
+			events: []string{testEvent(
+				"UID:exdate",
+				"DTSTART:20240108T080000Z",
+				"DTEND:20240108T093000Z",
+				"RRULE:FREQ=WEEKLY;UNTIL=20240129T080000Z",
+				"EXDATE:20240115T080000Z",
+			)},
+			want: []string{"20240108T080000Z", "20240122T080000Z", "20240129T080000Z"},
+		},
+		{
+			name: "BYDAY mit INTERVAL",
+			events: []string{testEvent(
+				"UID:byday",
+				"DTSTART:20240108T080000Z",
+				"DTEND:20240108T093000Z",
+				"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4",
+			)},
+			want: []string{"20240108T080000Z", "20240110T080000Z", "20240122T080000Z", "20240124T080000Z"},
+		},
+		{
+			name: "letzter Freitag im Monat",
+			events: []string{testEvent(
+				"UID:monthly",
+				"DTSTART:20240126T080000Z",
+				"DTEND:20240126T093000Z",
+				"RRULE:FREQ=MONTHLY;BYDAY=-1FR",
+			)},
+			want: []string{"20240126T080000Z", "20240223T080000Z"},
+		},
+		{
+			name: "Serie vor from wird übersprungen",
+			events: []string{testEvent(
+				"UID:old",
+				"DTSTART:20231218T080000Z",
+				"DTEND:20231218T093000Z",
+				"RRULE:FREQ=WEEKLY;COUNT=4",
+			)},
+			want: []string{"20240101T080000Z", "20240108T080000Z"},
+		},
+		{
+			name: "RDATE ergänzt die Serie",
+			events: []string{testEvent(
+				"UID:rdate",
+				"DTSTART:20240108T080000Z",
+				"DTEND:20240108T093000Z",
+				"RRULE:FREQ=WEEKLY;COUNT=2",
+				"RDATE:20240120T100000Z",
+			)},
+			want: []string{"20240108T080000Z", "20240115T080000Z", "20240120T100000Z"},
+		},
+		{
+			name: "verschobener Einzeltermin",
+			events: []string{
+				testEvent(
+					"UID:moved",
+					"DTSTART:20240108T080000Z",
+					"DTEND:20240108T093000Z",
+					"RRULE:FREQ=WEEKLY;COUNT=2",
+				),
+				testEvent(
+					"UID:moved",
+					"RECURRENCE-ID:20240115T080000Z",
+					"DTSTART:20240116T120000Z",
+					"DTEND:20240116T133000Z",
+				),
+			},
+			want: []string{"20240108T080000Z", "20240116T120000Z"},
+		},
+		{
+			name: "Termin nach until fehlt",
+			events: []string{testEvent(
+				"UID:late",
+				"DTSTART:20240108T080000Z",
+				"DTEND:20240108T093000Z",
+				"RRULE:FREQ=MONTHLY;COUNT=5",
+			)},
+			want: []string{"20240108T080000Z", "20240208T080000Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences := expandCalendar(parseTestCalendar(t, tt.events...), from, until)
+
+			var got []string
+			for _, occ := range occurrences {
+				got = append(got, occ.Start.UTC().Format(icalUIDTimeFormat))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Startzeiten = %v, erwartet %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandCalendarKeepsDuration(t *testing.T) {
+	cal := parseTestCalendar(t, testEvent(
+		"UID:duration",
+		"DTSTART:20240108T080000Z",
+		"DTEND:20240108T093000Z",
+		"RRULE:FREQ=DAILY;COUNT=2",
+	))
+
+	occurrences := expandCalendar(cal, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	if len(occurrences) != 2 {
+		t.Fatalf("%d Termine, erwartet 2", len(occurrences))
+	}
+	for _, occ := range occurrences {
+		if occ.End.Sub(occ.Start) != 90*time.Minute {
+			t.Errorf("Dauer von %s = %v, erwartet 1h30m", occ.UID, occ.End.Sub(occ.Start))
+		}
+		if !strings.HasPrefix(occ.UID, "duration/") {
+			t.Errorf("UID %q ohne Startzeit der Serie", occ.UID)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	reminderModeDM      = "dm"
+	reminderModeChannel = "kanal"
+
+	// Wie lange versendete Erinnerungen gegen doppeltes Auslösen gemerkt werden
+	reminderLogRetention = 30 * 24 * time.Hour
+)
+
+type reminderSubscription struct {
+	UserID  string
+	Minutes int
+	Mode    string
+}
+
+// RemindCommand verarbeitet /remind on|off|status
+func RemindCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	userID := interactionUserID(m)
+
+	switch options[0].Name {
+	case "on":
+		if trackerForGuild(m.GuildID) == nil {
+			respondEphemeral(s, m, "Für diesen Server ist noch kein Timer eingerichtet. Nutze /timer setup.")
+			return
+		}
+
+		sub := reminderSubscription{UserID: userID, Minutes: 10, Mode: reminderModeDM}
+		for _, opt := range options[0].Options {
+			switch opt.Name {
+			case "minuten":
+				sub.Minutes = int(opt.IntValue())
+			case "modus":
+				sub.Mode = opt.StringValue()
+			}
+		}
+
+		if err := saveReminderSubscription(db, m.GuildID, sub); err != nil {
+			log.Printf("Fehler bei /remind on: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern der Erinnerung.")
+			return
+		}
+
+		target := "per Direktnachricht"
+		if sub.Mode == reminderModeChannel {
+			target = "im Vorlesungskanal"
+		}
+		respondEphemeral(s, m, fmt.Sprintf("⏰ Du wirst %d Minuten vor jeder Vorlesung %s erinnert.", sub.Minutes, target))
+
+	case "off":
+		if err := deleteReminderSubscription(db, m.GuildID, userID); err != nil {
+			log.Printf("Fehler bei /remind off: %v", err)
+			respondEphemeral(s, m, "Fehler beim Entfernen der Erinnerung.")
+			return
+		}
+		respondEphemeral(s, m, "🔕 Erinnerungen deaktiviert.")
+
+	case "status":
+		subs, err := loadReminderSubscriptions(db, m.GuildID)
+		if err != nil {
+			log.Printf("Fehler bei /remind status: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden der Erinnerungen.")
+			return
+		}
+		for _, sub := range subs {
+			if sub.UserID == userID {
+				respondEphemeral(s, m, fmt.Sprintf("⏰ Erinnerung aktiv: %d Minuten vorher (%s).", sub.Minutes, sub.Mode))
+				return
+			}
+		}
+		respondEphemeral(s, m, "Du hast keine Erinnerungen aktiviert. Nutze /remind on.")
+
+	default:
+		log.Printf("Unbekannter /remind Unterbefehl: %s", options[0].Name)
+	}
+}
+
+// sendReminders verschickt fällige Erinnerungen für die kommenden Vorlesungen
+func (t *lectureTracker) sendReminders(s *discordgo.Session) {
+	subs, err := loadReminderSubscriptions(t.db, t.config.GuildID)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Erinnerungen:", err)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	maxMinutes := 0
+	for _, sub := range subs {
+		if sub.Minutes > maxMinutes {
+			maxMinutes = sub.Minutes
+		}
+	}
+
+	now := time.Now()
+	lectures, err := t.upcomingLectures(now, now.Add(time.Duration(maxMinutes)*time.Minute+time.Minute))
+	if err != nil {
+		fmt.Println("Fehler beim Abrufen des Kalenders für Erinnerungen:", err)
+		return
+	}
+
+	// Vorlesungen eines Wahlfachs gehen nur an dessen Teilnehmer
+	audience, err := loadTrackAudience(t.db, t.config.GuildID)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Wahlfächer für Erinnerungen:", err)
+	}
+
+	for _, lecture := range lectures {
+		var mentions []string
+
+		for _, sub := range subs {
+			if !audience.includes(lecture, sub.UserID) {
+				continue
+			}
+
+			remindAt := lecture.Start.Add(-time.Duration(sub.Minutes) * time.Minute)
+			if now.Before(remindAt) {
+				continue
+			}
+
+			// Erst vermerken, dann senden: lieber eine Nachricht verlieren (Absturz zwischen
+			// beiden Schritten) als sie doppelt zu senden, z.B. bei mehreren Bot-Instanzen.
+			// Die Countdown-Benachrichtigungen verfahren genauso.
+			claimed, err := claimReminder(t.db, t.config.GuildID, sub.UserID, lectureKey(lecture))
+			if err != nil {
+				fmt.Println("Fehler beim Vermerken der Erinnerung:", err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+
+			if sub.Mode == reminderModeChannel {
+				mentions = append(mentions, fmt.Sprintf("<@%s>", sub.UserID))
+				continue
+			}
+			if err := sendDirectEmbed(s, sub.UserID, reminderEmbed(lecture)); err != nil {
+				fmt.Printf("Fehler beim Senden der Erinnerung an %s: %v\n", sub.UserID, err)
+			}
+		}
+
+		// Bei vielen Abonnenten auf mehrere Nachrichten verteilen, das Embed nur in der ersten
+		for i, content := range chunkMentions(mentions) {
+			message := &discordgo.MessageSend{Content: content}
+			if i == 0 {
+				message.Embeds = []*discordgo.MessageEmbed{reminderEmbed(lecture)}
+			}
+			if _, err := s.ChannelMessageSendComplex(t.config.ChannelID, message); err != nil {
+				fmt.Println("Fehler beim Senden der Erinnerung im Kanal:", err)
+			}
+		}
+	}
+}
+
+// chunkMentions fasst Erwähnungen zu Nachrichten zusammen, die unter Discords Grenze von
+// 2000 Zeichen bleiben
+func chunkMentions(mentions []string) []string {
+	var chunks []string
+	var b strings.Builder
+	for _, mention := range mentions {
+		if b.Len() > 0 && b.Len()+len(mention)+1 > 1950 {
+			chunks = append(chunks, b.String())
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(mention)
+	}
+	if b.Len() > 0 {
+		chunks = append(chunks, b.String())
+	}
+	return chunks
+}
+
+// sendDirectEmbed schickt einem Nutzer ein Embed als Direktnachricht
+func sendDirectEmbed(s *discordgo.Session, userID string, embed *discordgo.MessageEmbed) error {
+	channel, err := s.UserChannelCreate(userID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Öffnen der DM: %w", err)
+	}
+	_, err = s.ChannelMessageSendEmbed(channel.ID, embed)
+	return err
+}
+
+func reminderEmbed(lecture LectureEvent) *discordgo.MessageEmbed {
+	fields := []*discordgo.MessageEmbedField{
+		{
+			Name:   "Beginn",
+			Value:  fmt.Sprintf("%s (<t:%d:R>)", lecture.Start.Format("15:04"), lecture.Start.Unix()),
+			Inline: true,
+		},
+	}
+	fields = append(fields, lectureDetailFields(lecture)...)
+
+	return &discordgo.MessageEmbed{
+		Title:       "⏰ Gleich geht's los: " + lecture.Name,
+		Description: fmt.Sprintf("%s - %s", lecture.Start.Format("15:04"), lecture.End.Format("15:04")),
+		Color:       0x00ccff,
+		Fields:      fields,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// lectureKey identifiziert eine einzelne Vorlesung, auch wenn sie verschoben wird
+func lectureKey(lecture LectureEvent) string {
+	return snapshotKey(lecture) + "@" + lecture.Start.UTC().Format(time.RFC3339)
+}
+
+func loadReminderSubscriptions(db *sql.DB, guildID string) ([]reminderSubscription, error) {
+	rows, err := db.Query("SELECT user_id, minutes, mode FROM reminder_subscriptions WHERE guild_id = $1", guildID)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Erinnerungen: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []reminderSubscription
+	for rows.Next() {
+		var sub reminderSubscription
+		if err := rows.Scan(&sub.UserID, &sub.Minutes, &sub.Mode); err != nil {
+			return nil, fmt.Errorf("fehler beim Lesen der Erinnerungen: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func saveReminderSubscription(db *sql.DB, guildID string, sub reminderSubscription) error {
+	_, err := db.Exec(`INSERT INTO reminder_subscriptions (guild_id, user_id, minutes, mode)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (guild_id, user_id) DO UPDATE SET minutes = EXCLUDED.minutes, mode = EXCLUDED.mode`,
+		guildID, sub.UserID, sub.Minutes, sub.Mode)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Erinnerung: %w", err)
+	}
+	return nil
+}
+
+func deleteReminderSubscription(db *sql.DB, guildID, userID string) error {
+	_, err := db.Exec("DELETE FROM reminder_subscriptions WHERE guild_id = $1 AND user_id = $2", guildID, userID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Löschen der Erinnerung: %w", err)
+	}
+	return nil
+}
+
+// claimReminder vermerkt eine Erinnerung als gesendet und liefert false, falls das schon geschehen ist
+func claimReminder(db *sql.DB, guildID, userID, lectureKey string) (bool, error) {
+	result, err := db.Exec(`INSERT INTO reminder_log (guild_id, user_id, lecture_key)
+		VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, guildID, userID, lectureKey)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func cleanupReminderLog(db *sql.DB) {
+	_, err := db.Exec("DELETE FROM reminder_log WHERE sent_at < $1", time.Now().Add(-reminderLogRetention))
+	if err != nil {
+		fmt.Println("Fehler beim Aufräumen der Erinnerungen:", err)
+	}
+}
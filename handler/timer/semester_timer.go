@@ -1,13 +1,15 @@
 package timer
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-const progressChannelID = "1328643078763843604"
+// Die Anzeigen werden bearbeitet statt neu gesendet, daher ist ein kurzes Intervall unkritisch
+const progressUpdateInterval = time.Hour
 
 func calculateProgress(start, end time.Time) (remaining int, percentage float64) {
 	now := time.Now()
@@ -27,23 +29,64 @@ func calculateProgress(start, end time.Time) (remaining int, percentage float64)
 	return
 }
 
-func sendProgressEmbeds(s *discordgo.Session) {
-	// Channel leeren (Purge)
-	messages, err := s.ChannelMessages(progressChannelID, 100, "", "", "")
-	if err == nil {
-		for _, msg := range messages {
-			s.ChannelMessageDelete(progressChannelID, msg.ID)
-		}
+// updateProgressEmbeds aktualisiert die Studien-, Semester- und Phasenanzeige einer Gilde
+func updateProgressEmbeds(s *discordgo.Session, db *sql.DB, cfg GuildConfig) {
+	periods, err := loadStudyPeriods(db, cfg.GuildID, cfg.Location())
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Semester:", err)
+		return
 	}
 
+	now := time.Now()
+
 	// Fortschritt gesamtes Studium
-	studyStart := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
-	studyEnd := time.Date(2026, 9, 30, 23, 59, 59, 0, time.UTC)
-	remaining, percentage := calculateProgress(studyStart, studyEnd)
+	embedStudy := placeholderEmbed("Fortschritt des gesamten Studiums", "Kein Studienzeitraum eingetragen. Nutze /semester add.")
+	var filesStudy []*discordgo.File
+	if study := currentPeriod(periods, periodStudy, now); study != nil {
+		embedStudy, filesStudy = progressEmbed(
+			"Fortschritt des gesamten Studiums",
+			"Der Fortschritt des gesamten Studiums im Überblick:",
+			*study)
+	}
+	publishProgressEmbed(s, db, cfg, progressMessageStudy, embedStudy, filesStudy, false)
 
-	embedStudy := &discordgo.MessageEmbed{
-		Title:       "Fortschritt des gesamten Studiums",
-		Description: "Der Fortschritt des gesamten Studiums im Überblick:",
+	// Fortschritt des aktuellen Semesters, außerhalb eines Semesters wird auf die aktuelle Phase verwiesen
+	noSemester := "Kein aktives Semester gefunden."
+	if phase := currentPhase(periods, now); phase != nil {
+		noSemester = fmt.Sprintf("Gerade kein Semester, aktuell läuft: %s", phaseTitle(*phase))
+	}
+	embedSemester := placeholderEmbed("Fortschritt des Semesters", noSemester)
+	var filesSemester []*discordgo.File
+	if semester := currentPeriod(periods, periodSemester, now); semester != nil {
+		embedSemester, filesSemester = progressEmbed(
+			"Fortschritt: "+semester.Name,
+			"Der Fortschritt des aktuellen Semesters:",
+			*semester)
+	}
+	publishProgressEmbed(s, db, cfg, progressMessageSemester, embedSemester, filesSemester, false)
+
+	// Theorie-, Praxis-, Prüfungsphase oder Ferien
+	embedPhase, filesPhase := phaseEmbed(periods, now)
+	publishProgressEmbed(s, db, cfg, progressMessagePhase, embedPhase, filesPhase, false)
+}
+
+// placeholderEmbed ersetzt eine Fortschrittsanzeige, solange es keinen passenden Zeitraum gibt
+func placeholderEmbed(title, description string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description,
+		Color:       0x999999,
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// progressEmbed erstellt ein Fortschritts-Embed für einen Zeitraum samt Fortschrittskarte
+func progressEmbed(title, description string, period studyPeriod) (*discordgo.MessageEmbed, []*discordgo.File) {
+	remaining, percentage := calculateProgress(period.Bounds())
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: description,
 		Color:       0x00ccff,
 		Fields: []*discordgo.MessageEmbedField{
 			{
@@ -64,72 +107,33 @@ func sendProgressEmbeds(s *discordgo.Session) {
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
+	return embed, withProgressCard(embed, percentage, remaining)
+}
 
-	s.ChannelMessageSendEmbed(progressChannelID, embedStudy)
-
-	// Fortschritt des aktuellen Semesters
-	semesters := []struct {
-		Semester int
-		Start    time.Time
-		End      time.Time
-	}{
-		{4, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 27, 23, 59, 59, 0, time.UTC)},
-		{5, time.Date(2025, 9, 29, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 20, 23, 59, 59, 0, time.UTC)},
-		{6, time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 14, 23, 59, 59, 0, time.UTC)},
-	}
-
-	var currentSemester *struct {
-		Semester int
-		Start    time.Time
-		End      time.Time
-	}
-
-	now := time.Now()
-	for _, semester := range semesters {
-		if now.After(semester.Start) && now.Before(semester.End) {
-			currentSemester = &semester
-			break
-		}
+// updateProgress aktualisiert die Fortschrittsanzeigen aller Gilden mit Fortschrittskanal
+func updateProgress(s *discordgo.Session, db *sql.DB) {
+	configs, err := loadGuildConfigs(db)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Timer-Konfigurationen:", err)
+		return
 	}
 
-	if currentSemester != nil {
-		remaining, percentage = calculateProgress(currentSemester.Start, currentSemester.End)
-		embedSemester := &discordgo.MessageEmbed{
-			Title:       fmt.Sprintf("Fortschritt des %d. Semesters", currentSemester.Semester),
-			Description: "Der Fortschritt des aktuellen Semesters:",
-			Color:       0x00ccff,
-			Fields: []*discordgo.MessageEmbedField{
-				{
-					Name:   "Verbleibende Zeit",
-					Value:  formatTimeFromMinutes(remaining),
-					Inline: true,
-				},
-				{
-					Name:   "Fortschritt",
-					Value:  fmt.Sprintf("%.1f %%", percentage),
-					Inline: true,
-				},
-				{
-					Name:   "Fortschrittsbalken",
-					Value:  createProgressBar(percentage, 20),
-					Inline: false,
-				},
-			},
-			Timestamp: time.Now().Format(time.RFC3339),
+	for _, cfg := range configs {
+		if cfg.ProgressChannelID == "" {
+			continue
 		}
-			s.ChannelMessageSendEmbed(progressChannelID, embedSemester)
-	} else {
-		s.ChannelMessageSend(progressChannelID, "Kein aktives Semester gefunden.")
+		updateProgressEmbeds(s, db, cfg)
+		updateExamCountdown(s, db, cfg)
 	}
 }
 
-func StartProgressUpdater(s *discordgo.Session) {
-	fmt.Println("Starte Fortschrittsaktualisierung alle 24 Stunden.")
-	sendProgressEmbeds(s)
-	ticker := time.NewTicker(24 * time.Hour)
+func StartProgressUpdater(s *discordgo.Session, db *sql.DB) {
+	fmt.Println("Starte Fortschrittsaktualisierung jede Stunde.")
+	updateProgress(s, db)
+	ticker := time.NewTicker(progressUpdateInterval)
 	go func() {
 		for range ticker.C {
-			sendProgressEmbeds(s)
+			updateProgress(s, db)
 		}
 	}()
 }
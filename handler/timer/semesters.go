@@ -0,0 +1,277 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Arten von Zeiträumen im Studienverlauf
+const (
+	periodStudy    = "studium"
+	periodSemester = "semester"
+	periodTheory   = "theorie"
+	periodPractice = "praxis"
+	periodExams    = "pruefung"
+	periodHoliday  = "ferien"
+)
+
+var periodLabels = map[string]string{
+	periodStudy:    "Studium",
+	periodSemester: "Semester",
+	periodTheory:   "Theoriephase",
+	periodPractice: "Praxisphase",
+	periodExams:    "Prüfungsphase",
+	periodHoliday:  "Ferien",
+}
+
+// studyPeriod ist ein Zeitraum im Studienverlauf, Start und Ende sind ganze Tage (inklusive)
+type studyPeriod struct {
+	ID    int
+	Kind  string
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+// Bounds liefert Beginn und exklusives Ende des Zeitraums
+func (p studyPeriod) Bounds() (time.Time, time.Time) {
+	return p.Start, p.End.AddDate(0, 0, 1)
+}
+
+// Contains prüft, ob der Zeitpunkt innerhalb des Zeitraums liegt
+func (p studyPeriod) Contains(t time.Time) bool {
+	start, end := p.Bounds()
+	return !t.Before(start) && t.Before(end)
+}
+
+// currentPeriod liefert den Zeitraum einer Art, in dem now liegt, oder nil
+func currentPeriod(periods []studyPeriod, kind string, now time.Time) *studyPeriod {
+	for _, period := range periods {
+		if period.Kind == kind && period.Contains(now) {
+			return &period
+		}
+	}
+	return nil
+}
+
+// SemesterCommand verarbeitet /semester add|edit|remove|list
+func SemesterCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+		respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um Semester zu verwalten.")
+		return
+	}
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range options[0].Options {
+		values[opt.Name] = opt
+	}
+
+	loc := guildLocation(db, m.GuildID)
+
+	switch options[0].Name {
+	case "add":
+		period := studyPeriod{
+			Kind: values["typ"].StringValue(),
+			Name: strings.TrimSpace(values["name"].StringValue()),
+		}
+
+		var err error
+		if period.Start, period.End, err = parsePeriodDates(values["start"].StringValue(), values["ende"].StringValue(), loc); err != nil {
+			respondEphemeral(s, m, err.Error())
+			return
+		}
+
+		id, err := addStudyPeriod(db, m.GuildID, period)
+		if err != nil {
+			log.Printf("Fehler bei /semester add: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern des Zeitraums.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("✅ %s #%d **%s** (%s) gespeichert.", periodLabels[period.Kind], id, period.Name, formatPeriodRange(period)))
+
+	case "edit":
+		id := int(values["id"].IntValue())
+		period, err := loadStudyPeriod(db, m.GuildID, id, loc)
+		if err != nil {
+			log.Printf("Fehler bei /semester edit: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden des Zeitraums.")
+			return
+		}
+		if period == nil {
+			respondEphemeral(s, m, fmt.Sprintf("Zeitraum #%d wurde nicht gefunden.", id))
+			return
+		}
+
+		if opt, ok := values["name"]; ok {
+			period.Name = strings.TrimSpace(opt.StringValue())
+		}
+		start, end := period.Start.Format("02.01.2006"), period.End.Format("02.01.2006")
+		if opt, ok := values["start"]; ok {
+			start = opt.StringValue()
+		}
+		if opt, ok := values["ende"]; ok {
+			end = opt.StringValue()
+		}
+		if period.Start, period.End, err = parsePeriodDates(start, end, loc); err != nil {
+			respondEphemeral(s, m, err.Error())
+			return
+		}
+
+		if err := updateStudyPeriod(db, m.GuildID, *period); err != nil {
+			log.Printf("Fehler bei /semester edit: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern des Zeitraums.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("✅ %s #%d **%s** (%s) aktualisiert.", periodLabels[period.Kind], id, period.Name, formatPeriodRange(*period)))
+
+	case "remove":
+		id := int(values["id"].IntValue())
+		deleted, err := deleteStudyPeriod(db, m.GuildID, id)
+		if err != nil {
+			log.Printf("Fehler bei /semester remove: %v", err)
+			respondEphemeral(s, m, "Fehler beim Löschen des Zeitraums.")
+			return
+		}
+		if !deleted {
+			respondEphemeral(s, m, fmt.Sprintf("Zeitraum #%d wurde nicht gefunden.", id))
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("🗑️ Zeitraum #%d gelöscht.", id))
+
+	case "list":
+		periods, err := loadStudyPeriods(db, m.GuildID, loc)
+		if err != nil {
+			log.Printf("Fehler bei /semester list: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden der Zeiträume.")
+			return
+		}
+		if len(periods) == 0 {
+			respondEphemeral(s, m, "Es sind noch keine Zeiträume eingetragen. Nutze /semester add.")
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString("🗓️ **Studienverlauf**\n")
+		for _, period := range periods {
+			fmt.Fprintf(&b, "`#%d` %s **%s**: %s\n", period.ID, periodLabels[period.Kind], period.Name, formatPeriodRange(period))
+		}
+		respondEphemeral(s, m, b.String())
+
+	default:
+		log.Printf("Unbekannter /semester Unterbefehl: %s", options[0].Name)
+	}
+}
+
+// parsePeriodDates liest Beginn und Ende im Format TT.MM.JJJJ
+func parsePeriodDates(startValue, endValue string, loc *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("02.01.2006", strings.TrimSpace(startValue), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Ungültiges Startdatum, erwartet wird TT.MM.JJJJ.")
+	}
+	end, err := time.ParseInLocation("02.01.2006", strings.TrimSpace(endValue), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("Ungültiges Enddatum, erwartet wird TT.MM.JJJJ.")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Das Enddatum darf nicht vor dem Startdatum liegen.")
+	}
+	return start, end, nil
+}
+
+func formatPeriodRange(period studyPeriod) string {
+	return period.Start.Format("02.01.2006") + " - " + period.End.Format("02.01.2006")
+}
+
+// loadStudyPeriods lädt alle Zeiträume einer Gilde, Datumswerte in der Zeitzone der Gilde
+func loadStudyPeriods(db *sql.DB, guildID string, loc *time.Location) ([]studyPeriod, error) {
+	rows, err := db.Query(`SELECT id, kind, name, TO_CHAR(start_date, 'YYYY-MM-DD'), TO_CHAR(end_date, 'YYYY-MM-DD')
+		FROM study_periods WHERE guild_id = $1 ORDER BY start_date, id`, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden der Zeiträume: %w", err)
+	}
+	defer rows.Close()
+
+	var periods []studyPeriod
+	for rows.Next() {
+		period, err := scanStudyPeriod(rows, loc)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	return periods, rows.Err()
+}
+
+func loadStudyPeriod(db *sql.DB, guildID string, id int, loc *time.Location) (*studyPeriod, error) {
+	row := db.QueryRow(`SELECT id, kind, name, TO_CHAR(start_date, 'YYYY-MM-DD'), TO_CHAR(end_date, 'YYYY-MM-DD')
+		FROM study_periods WHERE guild_id = $1 AND id = $2`, guildID, id)
+	period, err := scanStudyPeriod(row, loc)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// Datumswerte werden als Text gelesen, damit sie unabhängig von der Zeitzone der Datenbank sind
+func scanStudyPeriod(row interface{ Scan(...any) error }, loc *time.Location) (studyPeriod, error) {
+	var period studyPeriod
+	var start, end string
+	if err := row.Scan(&period.ID, &period.Kind, &period.Name, &start, &end); err != nil {
+		if err == sql.ErrNoRows {
+			return period, err
+		}
+		return period, fmt.Errorf("fehler beim Lesen der Zeiträume: %w", err)
+	}
+
+	var err error
+	if period.Start, err = time.ParseInLocation("2006-01-02", start, loc); err != nil {
+		return period, fmt.Errorf("fehler beim Lesen der Zeiträume: %w", err)
+	}
+	if period.End, err = time.ParseInLocation("2006-01-02", end, loc); err != nil {
+		return period, fmt.Errorf("fehler beim Lesen der Zeiträume: %w", err)
+	}
+	return period, nil
+}
+
+func addStudyPeriod(db *sql.DB, guildID string, period studyPeriod) (int, error) {
+	var id int
+	err := db.QueryRow(`INSERT INTO study_periods (guild_id, kind, name, start_date, end_date)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		guildID, period.Kind, period.Name, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02")).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("fehler beim Speichern des Zeitraums: %w", err)
+	}
+	return id, nil
+}
+
+func updateStudyPeriod(db *sql.DB, guildID string, period studyPeriod) error {
+	_, err := db.Exec(`UPDATE study_periods SET name = $1, start_date = $2, end_date = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE guild_id = $4 AND id = $5`,
+		period.Name, period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"), guildID, period.ID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern des Zeitraums: %w", err)
+	}
+	return nil
+}
+
+func deleteStudyPeriod(db *sql.DB, guildID string, id int) (bool, error) {
+	result, err := db.Exec("DELETE FROM study_periods WHERE guild_id = $1 AND id = $2", guildID, id)
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Löschen des Zeitraums: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
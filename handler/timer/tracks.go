@@ -0,0 +1,297 @@
+package timer
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// lectureTrack ist ein Wahlfach, dessen Vorlesungen über ein Muster auf den Titel erkannt werden
+type lectureTrack struct {
+	ID      int
+	Name    string
+	Pattern string
+	regex   *regexp.Regexp
+}
+
+// trackAudience enthält alle Wahlfächer einer Gilde mit ihren Teilnehmern
+type trackAudience struct {
+	tracks  []lectureTrack
+	members map[int]map[string]bool
+}
+
+// tracksFor liefert alle Wahlfächer, zu denen eine Vorlesung gehört
+func (a *trackAudience) tracksFor(lecture LectureEvent) []lectureTrack {
+	var matched []lectureTrack
+	for _, track := range a.tracks {
+		if track.regex.MatchString(lecture.Name) {
+			matched = append(matched, track)
+		}
+	}
+	return matched
+}
+
+// includes prüft, ob ein Nutzer über eine Vorlesung benachrichtigt wird:
+// Vorlesungen ohne Wahlfach gelten für alle, sonst nur für die Teilnehmer
+func (a *trackAudience) includes(lecture LectureEvent, userID string) bool {
+	matched := a.tracksFor(lecture)
+	if len(matched) == 0 {
+		return true
+	}
+	for _, track := range matched {
+		if a.members[track.ID][userID] {
+			return true
+		}
+	}
+	return false
+}
+
+// membersFor liefert alle Teilnehmer der Wahlfächer einer Vorlesung
+func (a *trackAudience) membersFor(lecture LectureEvent) []string {
+	seen := make(map[string]bool)
+	var users []string
+	for _, track := range a.tracksFor(lecture) {
+		for userID := range a.members[track.ID] {
+			if !seen[userID] {
+				seen[userID] = true
+				users = append(users, userID)
+			}
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+// trackMentions liefert die Erwähnungen der Wahlfach-Teilnehmer für eine Vorlesung
+func (t *lectureTracker) trackMentions(lecture LectureEvent) string {
+	audience, err := loadTrackAudience(t.db, t.config.GuildID)
+	if err != nil {
+		fmt.Println("Fehler beim Laden der Wahlfächer:", err)
+		return ""
+	}
+
+	members := audience.membersFor(lecture)
+	var b strings.Builder
+	for i, userID := range members {
+		mention := fmt.Sprintf("<@%s>", userID)
+		// Discord erlaubt maximal 2000 Zeichen pro Nachricht, Platz für den Hinweis lassen
+		if b.Len()+len(mention)+1 > 1950 {
+			fmt.Fprintf(&b, " … und %d weitere", len(members)-i)
+			break
+		}
+		if b.Len() > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(mention)
+	}
+	return b.String()
+}
+
+// TrackCommand verarbeitet /track wählen|abwählen|list|add|remove
+func TrackCommand(s *discordgo.Session, m *discordgo.InteractionCreate, db *sql.DB) {
+	options := m.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return
+	}
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range options[0].Options {
+		values[opt.Name] = opt
+	}
+
+	userID := interactionUserID(m)
+
+	switch options[0].Name {
+	case "wählen", "abwählen":
+		track, err := findTrack(db, m.GuildID, values["name"].StringValue())
+		if err != nil {
+			log.Printf("Fehler bei /track %s: %v", options[0].Name, err)
+			respondEphemeral(s, m, "Fehler beim Laden der Wahlfächer.")
+			return
+		}
+		if track == nil {
+			respondEphemeral(s, m, "Dieses Wahlfach gibt es nicht. Alle Wahlfächer siehst du mit /track list.")
+			return
+		}
+
+		if options[0].Name == "wählen" {
+			err = joinTrack(db, m.GuildID, userID, track.ID)
+		} else {
+			err = leaveTrack(db, m.GuildID, userID, track.ID)
+		}
+		if err != nil {
+			log.Printf("Fehler bei /track %s: %v", options[0].Name, err)
+			respondEphemeral(s, m, "Fehler beim Speichern der Auswahl.")
+			return
+		}
+
+		if options[0].Name == "wählen" {
+			respondEphemeral(s, m, fmt.Sprintf("✅ Du bist jetzt in **%s** und wirst bei dessen Vorlesungen erwähnt.", track.Name))
+		} else {
+			respondEphemeral(s, m, fmt.Sprintf("👋 Du hast **%s** abgewählt.", track.Name))
+		}
+
+	case "list":
+		audience, err := loadTrackAudience(db, m.GuildID)
+		if err != nil {
+			log.Printf("Fehler bei /track list: %v", err)
+			respondEphemeral(s, m, "Fehler beim Laden der Wahlfächer.")
+			return
+		}
+		if len(audience.tracks) == 0 {
+			respondEphemeral(s, m, "Es sind noch keine Wahlfächer eingerichtet.")
+			return
+		}
+
+		var b strings.Builder
+		b.WriteString("📋 **Wahlfächer**\n")
+		for _, track := range audience.tracks {
+			marker := "▫️"
+			if audience.members[track.ID][userID] {
+				marker = "✅"
+			}
+			fmt.Fprintf(&b, "%s **%s** (%d Teilnehmer) ~ `%s`\n", marker, track.Name, len(audience.members[track.ID]), track.Pattern)
+		}
+		respondEphemeral(s, m, b.String())
+
+	case "add":
+		if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+			respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um Wahlfächer anzulegen.")
+			return
+		}
+
+		name := strings.TrimSpace(values["name"].StringValue())
+		pattern := values["muster"].StringValue()
+		if _, err := compileFilterPattern(pattern); err != nil {
+			respondEphemeral(s, m, fmt.Sprintf("Ungültiger regulärer Ausdruck: %v", err))
+			return
+		}
+
+		if err := addTrack(db, m.GuildID, name, pattern); err != nil {
+			log.Printf("Fehler bei /track add: %v", err)
+			respondEphemeral(s, m, "Fehler beim Speichern des Wahlfachs.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("✅ Wahlfach **%s** gespeichert.", name))
+
+	case "remove":
+		if m.Member == nil || m.Member.Permissions&discordgo.PermissionManageServer == 0 {
+			respondEphemeral(s, m, "Du benötigst die Berechtigung \"Server verwalten\", um Wahlfächer zu löschen.")
+			return
+		}
+
+		name := values["name"].StringValue()
+		deleted, err := deleteTrack(db, m.GuildID, name)
+		if err != nil {
+			log.Printf("Fehler bei /track remove: %v", err)
+			respondEphemeral(s, m, "Fehler beim Löschen des Wahlfachs.")
+			return
+		}
+		if !deleted {
+			respondEphemeral(s, m, "Dieses Wahlfach gibt es nicht.")
+			return
+		}
+		respondEphemeral(s, m, fmt.Sprintf("🗑️ Wahlfach **%s** gelöscht.", name))
+
+	default:
+		log.Printf("Unbekannter /track Unterbefehl: %s", options[0].Name)
+	}
+}
+
+func loadTrackAudience(db *sql.DB, guildID string) (*trackAudience, error) {
+	audience := &trackAudience{members: make(map[int]map[string]bool)}
+
+	rows, err := db.Query("SELECT id, name, pattern FROM lecture_tracks WHERE guild_id = $1 ORDER BY name", guildID)
+	if err != nil {
+		return audience, fmt.Errorf("fehler beim Laden der Wahlfächer: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var track lectureTrack
+		if err := rows.Scan(&track.ID, &track.Name, &track.Pattern); err != nil {
+			return audience, fmt.Errorf("fehler beim Lesen der Wahlfächer: %w", err)
+		}
+		track.regex, err = compileFilterPattern(track.Pattern)
+		if err != nil {
+			fmt.Printf("Warnung: Ungültiges Muster für Wahlfach %q wird ignoriert: %v\n", track.Name, err)
+			continue
+		}
+		audience.tracks = append(audience.tracks, track)
+		audience.members[track.ID] = make(map[string]bool)
+	}
+	if err := rows.Err(); err != nil {
+		return audience, err
+	}
+
+	memberRows, err := db.Query("SELECT track_id, user_id FROM track_members WHERE guild_id = $1", guildID)
+	if err != nil {
+		return audience, fmt.Errorf("fehler beim Laden der Wahlfach-Teilnehmer: %w", err)
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var trackID int
+		var userID string
+		if err := memberRows.Scan(&trackID, &userID); err != nil {
+			return audience, fmt.Errorf("fehler beim Lesen der Wahlfach-Teilnehmer: %w", err)
+		}
+		if members, ok := audience.members[trackID]; ok {
+			members[userID] = true
+		}
+	}
+	return audience, memberRows.Err()
+}
+
+func findTrack(db *sql.DB, guildID, name string) (*lectureTrack, error) {
+	var track lectureTrack
+	err := db.QueryRow("SELECT id, name, pattern FROM lecture_tracks WHERE guild_id = $1 AND LOWER(name) = LOWER($2)", guildID, strings.TrimSpace(name)).
+		Scan(&track.ID, &track.Name, &track.Pattern)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fehler beim Laden des Wahlfachs: %w", err)
+	}
+	return &track, nil
+}
+
+func addTrack(db *sql.DB, guildID, name, pattern string) error {
+	_, err := db.Exec(`INSERT INTO lecture_tracks (guild_id, name, pattern) VALUES ($1, $2, $3)
+		ON CONFLICT (guild_id, name) DO UPDATE SET pattern = EXCLUDED.pattern`, guildID, name, pattern)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern des Wahlfachs: %w", err)
+	}
+	return nil
+}
+
+func deleteTrack(db *sql.DB, guildID, name string) (bool, error) {
+	result, err := db.Exec("DELETE FROM lecture_tracks WHERE guild_id = $1 AND LOWER(name) = LOWER($2)", guildID, strings.TrimSpace(name))
+	if err != nil {
+		return false, fmt.Errorf("fehler beim Löschen des Wahlfachs: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func joinTrack(db *sql.DB, guildID, userID string, trackID int) error {
+	_, err := db.Exec(`INSERT INTO track_members (guild_id, user_id, track_id) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, guildID, userID, trackID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Speichern der Wahlfach-Auswahl: %w", err)
+	}
+	return nil
+}
+
+func leaveTrack(db *sql.DB, guildID, userID string, trackID int) error {
+	_, err := db.Exec("DELETE FROM track_members WHERE guild_id = $1 AND user_id = $2 AND track_id = $3", guildID, userID, trackID)
+	if err != nil {
+		return fmt.Errorf("fehler beim Löschen der Wahlfach-Auswahl: %w", err)
+	}
+	return nil
+}
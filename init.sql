@@ -8,12 +8,12 @@ WHERE NOT EXISTS (SELECT FROM pg_database WHERE datname = 'discord_bot')\gexec
 -- Verbinde zur discord_bot Datenbank
 \c discord_bot;
 
--- Erstelle die Users Tabelle
+-- Erstelle die Users Tabelle (Guthaben in Cent, 1/100 Müller Coin)
 CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     user_id TEXT NOT NULL,
     guild_id TEXT NOT NULL,
-    balance REAL DEFAULT 1000,
+    balance BIGINT DEFAULT 100000,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(user_id, guild_id)
@@ -39,7 +39,7 @@ CREATE TRIGGER update_users_updated_at
     FOR EACH ROW 
     EXECUTE FUNCTION update_updated_at_column();
 
--- Optional: Erstelle eine Ansicht für Statistiken
+-- Optional: Erstelle eine Ansicht für Statistiken (Beträge in Cent)
 CREATE OR REPLACE VIEW user_stats AS
 SELECT 
     guild_id,
@@ -53,6 +53,13 @@ func main() {
 
 	log.Println("✅ PostgreSQL Datenbank erfolgreich initialisiert!")
 
+	// Slot-Maschine laden und prüfen, eine ungültige Datei verhindert den Start
+	machine, err := slots.LoadMachine()
+	if err != nil {
+		log.Fatalf("Fehler beim Laden der Slot-Maschine: %v", err)
+	}
+	log.Printf("🎰 Slot-Maschine geladen: %s", machine)
+
 	// Discord-Session mit Intents erstellen
 	intents := discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildMembers
 	dg, err := discordgo.New("Bot " + token)
@@ -82,7 +89,7 @@ func main() {
 				}
 
 				amount := m.ApplicationCommandData().Options[0].IntValue()
-				err := slots.MoneyAll(s, db, m.GuildID, int(amount))
+				err := slots.MoneyAll(s, db, m.GuildID, int(amount), m.Member.User.ID)
 				if err != nil {
 					log.Printf("Fehler bei MoneyAll: %v", err)
 					s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
@@ -116,7 +123,7 @@ func main() {
 				// Führe den eigentlichen Befehl aus
 				userID := m.ApplicationCommandData().Options[0].UserValue(nil).ID
 				amount := m.ApplicationCommandData().Options[1].IntValue()
-				err := slots.MoneyGive(db, userID, m.GuildID, int(amount))
+				err := slots.MoneyGive(db, userID, m.GuildID, int(amount), m.Member.User.ID)
 				if err != nil {
 					log.Printf("Fehler bei MoneyGive: %v", err)
 					s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
@@ -158,11 +165,40 @@ func main() {
 				s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
-						Content: fmt.Sprintf("Du hast aktuell %.0f Müller Coins.", balance),
+						Content: fmt.Sprintf("Du hast aktuell %s Müller Coins.", slots.FormatCoins(balance)),
 						Flags:   discordgo.MessageFlagsEphemeral,
 					},
 				})
 
+			case "slotmachine":
+				if m.Member.User.ID != ownerID {
+					s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData{
+							Content: "Du bist nicht berechtigt, diesen Befehl auszuführen.",
+							Flags:   discordgo.MessageFlagsEphemeral,
+						},
+					})
+					return
+				}
+				slots.MachineCommand(s, m)
+
+			case "history":
+				slots.HistoryCommand(s, m, db)
+
+			case "reconcile":
+				if m.Member.User.ID != ownerID {
+					s.InteractionRespond(m.Interaction, &discordgo.InteractionResponse{
+						Type: discordgo.InteractionResponseChannelMessageWithSource,
+						Data: &discordgo.InteractionResponseData{
+							Content: "Du bist nicht berechtigt, diesen Befehl auszuführen.",
+							Flags:   discordgo.MessageFlagsEphemeral,
+						},
+					})
+					return
+				}
+				slots.ReconcileCommand(s, m, db)
+
 			case "leaderboard":
 				// Aufruf des Leaderboard-Handlers
 				leaderboard.LeaderboardHandler(s, m, db)
@@ -171,10 +207,49 @@ func main() {
 				bet := m.ApplicationCommandData().Options[0].IntValue()
 				slots.AutoSlotCommand(s, m, db, int(bet))
 
+			case "timer":
+				timer.TimerCommand(s, m, db)
+
+			case "next":
+				timer.NextCommand(s, m)
+
+			case "today":
+				timer.TodayCommand(s, m)
+
+			case "week":
+				timer.WeekCommand(s, m)
+
+			case "remind":
+				timer.RemindCommand(s, m, db)
+
+			case "track":
+				timer.TrackCommand(s, m, db)
+
+			case "exam":
+				timer.ExamCommand(s, m, db)
+
+			case "semester":
+				timer.SemesterCommand(s, m, db)
+
+			case "countdown":
+				timer.CountdownCommand(s, m, db)
+
+			case "stats":
+				timer.StatsCommand(s, m, db)
+
 			default:
 				log.Printf("Unbekannter Befehl: %s", m.ApplicationCommandData().Name)
 			}
 
+		case discordgo.InteractionMessageComponent:
+			switch m.MessageComponentData().CustomID {
+			case timer.AttendanceButtonID:
+				timer.AttendanceButton(s, m, db)
+
+			default:
+				log.Printf("Unbekannte Komponente: %s", m.MessageComponentData().CustomID)
+			}
+
 		default:
 			log.Printf("Unbekannter Interaktionstyp: %v", m.Type)
 		}
@@ -276,6 +351,7 @@ func main() {
 				Description: "Einsatz für die Slotmachine (Mindestens 1)",
 				Required:    true,
 				MinValue:    &[]float64{1}[0],
+				MaxValue:    slots.MaxBet,
 			},
 		},
 	})
@@ -291,6 +367,43 @@ func main() {
 		log.Fatalf("Fehler beim Registrieren von /money: %v", err)
 	}
 
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:         "history",
+		Description:  "Zeigt deine letzten Buchungen an Müller Coins an",
+		DMPermission: &[]bool{false}[0],
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /history: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "1181238521734901770", &discordgo.ApplicationCommand{
+		Name:        "slotmachine",
+		Description: "Verwaltet Symbole, Gewinnlinien und Auszahlungen der Slot-Maschine",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reload",
+				Description: "Lädt die Maschinendefinition neu aus der JSON-Datei",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Exportiert die aktive Maschine als JSON-Datei",
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /slotmachine: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "1181238521734901770", &discordgo.ApplicationCommand{
+		Name:        "reconcile",
+		Description: "Prüft, ob alle Guthaben mit dem Buchungs-Ledger übereinstimmen",
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /reconcile: %v", err)
+	}
+
 	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
 		Name:        "leaderboard",
 		Description: "Zeigt die Rangliste der Spieler mit dem meisten Spielgeld an",
@@ -309,6 +422,7 @@ func main() {
 				Description: "Der Einsatz pro Runde (Mindestens 1)",
 				Required:    true,
 				MinValue:    &[]float64{1}[0],
+				MaxValue:    slots.MaxBet,
 			},
 		},
 	})
@@ -316,12 +430,550 @@ func main() {
 		log.Fatalf("Fehler beim Registrieren von /autoslot: %v", err)
 	}
 
+	manageServer := int64(discordgo.PermissionManageServer)
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:                     "timer",
+		Description:              "Konfiguriert den Vorlesungs-Timer für diesen Server",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "setup",
+				Description: "Legt Kalender, Kanal und Zeitzone für den Vorlesungs-Timer fest",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "kalender",
+						Description: "iCal-URL des Vorlesungsplans (z.B. https://stuv.app/MGH-TINF23/ical)",
+						Required:    true,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "kanal",
+						Description:  "Kanal, in dem der Vorlesungs-Timer angezeigt wird",
+						Required:     true,
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "zeitzone",
+						Description: "Zeitzone des Kalenders (Standard: Europe/Berlin)",
+						Required:    false,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "fortschrittskanal",
+						Description:  "Kanal für Studienfortschritt und Klausuren-Countdown",
+						Required:     false,
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "status",
+				Description: "Zeigt die aktuelle Timer-Konfiguration an",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "bloecke",
+				Description: "Legt fest, ab welcher Pause ein neuer Vorlesungsblock beginnt",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "pause",
+						Description: "Maximale Pause in Minuten innerhalb eines Blocks (Standard: 30)",
+						Required:    true,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    180,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "belohnung",
+				Description: "Legt fest, wie viele Müller Coins es fürs Einchecken in eine Vorlesung gibt",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "muenzen",
+						Description: "Müller Coins pro Vorlesung, 0 deaktiviert die Belohnung (Standard: 25)",
+						Required:    true,
+						MinValue:    &[]float64{0}[0],
+						MaxValue:    1000,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+				Name:        "filter",
+				Description: "Legt fest, welche Kalendertermine als Vorlesung gelten",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "add",
+						Description: "Fügt eine Filterregel hinzu",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "aktion",
+								Description: "Termine immer einbeziehen oder immer ausschließen",
+								Required:    true,
+								Choices: []*discordgo.ApplicationCommandOptionChoice{
+									{Name: "Einbeziehen", Value: "include"},
+									{Name: "Ausschließen", Value: "exclude"},
+								},
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "feld",
+								Description: "Feld, auf das die Regel angewendet wird",
+								Required:    true,
+								Choices: []*discordgo.ApplicationCommandOptionChoice{
+									{Name: "Titel", Value: "titel"},
+									{Name: "Kategorie", Value: "kategorie"},
+									{Name: "Ort", Value: "ort"},
+								},
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionString,
+								Name:        "muster",
+								Description: "Regulärer Ausdruck, z.B. Klausur|Feiertag",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "remove",
+						Description: "Entfernt eine Filterregel",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionInteger,
+								Name:        "id",
+								Description: "ID der Regel (siehe /timer filter list)",
+								Required:    true,
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "dauer",
+						Description: "Legt die erlaubte Dauer einer Vorlesung fest",
+						Options: []*discordgo.ApplicationCommandOption{
+							{
+								Type:        discordgo.ApplicationCommandOptionInteger,
+								Name:        "min",
+								Description: "Mindestdauer in Minuten (Standard: 0)",
+								Required:    false,
+								MinValue:    &[]float64{0}[0],
+							},
+							{
+								Type:        discordgo.ApplicationCommandOptionInteger,
+								Name:        "max",
+								Description: "Höchstdauer in Minuten, exklusiv (Standard: 240)",
+								Required:    false,
+								MinValue:    &[]float64{1}[0],
+							},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionSubCommand,
+						Name:        "list",
+						Description: "Zeigt alle Filterregeln an",
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /timer: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "next",
+		Description: "Zeigt die nächste Vorlesung mit Countdown an",
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /next: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "today",
+		Description: "Zeigt alle Vorlesungen von heute an",
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /today: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "week",
+		Description: "Zeigt die Vorlesungen der nächsten Tage an",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "tage",
+				Description: "Anzahl der Tage (Standard: 7, Maximal 14)",
+				Required:    false,
+				MinValue:    &[]float64{1}[0],
+				MaxValue:    14,
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /week: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "remind",
+		Description: "Erinnerungen vor Vorlesungsbeginn",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "on",
+				Description: "Aktiviert Erinnerungen vor jeder Vorlesung",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "minuten",
+						Description: "Wie viele Minuten vor Beginn erinnert wird (Standard: 10)",
+						Required:    false,
+						MinValue:    &[]float64{1}[0],
+						MaxValue:    180,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "modus",
+						Description: "Per Direktnachricht oder im Vorlesungskanal (Standard: DM)",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Direktnachricht", Value: "dm"},
+							{Name: "Vorlesungskanal", Value: "kanal"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "off",
+				Description: "Deaktiviert die Erinnerungen",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "status",
+				Description: "Zeigt deine aktuellen Erinnerungseinstellungen an",
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /remind: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "track",
+		Description: "Wahlfächer auswählen und verwalten",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "wählen",
+				Description: "Tritt einem Wahlfach bei, um bei dessen Vorlesungen erwähnt zu werden",
+				Options:     []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name des Wahlfachs",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "abwählen",
+				Description: "Verlässt ein Wahlfach",
+				Options:     []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name des Wahlfachs",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Zeigt alle Wahlfächer an",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Legt ein Wahlfach an (Server verwalten)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name des Wahlfachs",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "muster",
+						Description: "Regulärer Ausdruck auf den Vorlesungstitel, z.B. Robotik|Robotics",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Löscht ein Wahlfach (Server verwalten)",
+				Options:     []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Name des Wahlfachs",
+						Required:    true,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /track: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "exam",
+		Description: "Klausuren eintragen und anzeigen",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Trägt eine Klausur ein (Server verwalten)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "modul",
+						Description: "Modul der Klausur",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "datum",
+						Description: "Datum als TT.MM.JJJJ oder TT.MM.JJJJ HH:MM",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "raum",
+						Description: "Raum der Klausur",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Zeigt alle anstehenden Klausuren an",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Entfernt eine Klausur (Server verwalten)",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "ID der Klausur (siehe /exam list)",
+						Required:    true,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /exam: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:                     "semester",
+		Description:              "Verwaltet Studium, Semester, Phasen und Ferien für die Fortschrittsanzeige",
+		DefaultMemberPermissions: &manageServer,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Trägt einen neuen Zeitraum ein",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "typ",
+						Description: "Art des Zeitraums",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "Studium", Value: "studium"},
+							{Name: "Semester", Value: "semester"},
+							{Name: "Theoriephase", Value: "theorie"},
+							{Name: "Praxisphase", Value: "praxis"},
+							{Name: "Prüfungsphase", Value: "pruefung"},
+							{Name: "Ferien", Value: "ferien"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Bezeichnung, z.B. 5. Semester",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "start",
+						Description: "Erster Tag als TT.MM.JJJJ",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "ende",
+						Description: "Letzter Tag als TT.MM.JJJJ",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "edit",
+				Description: "Ändert einen eingetragenen Zeitraum",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "ID des Zeitraums (siehe /semester list)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Neue Bezeichnung",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "start",
+						Description: "Neuer erster Tag als TT.MM.JJJJ",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "ende",
+						Description: "Neuer letzter Tag als TT.MM.JJJJ",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Löscht einen Zeitraum",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "ID des Zeitraums (siehe /semester list)",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Zeigt alle eingetragenen Zeiträume an",
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /semester: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "countdown",
+		Description: "Persönliche Countdowns, z.B. bis zur Abgabe der Bachelorarbeit",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "Legt einen neuen Countdown an",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Wofür läuft der Countdown?",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "datum",
+						Description: "Zieldatum als TT.MM.JJJJ oder TT.MM.JJJJ HH:MM",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "dm",
+						Description: "Direktnachricht, wenn der Countdown abgelaufen ist",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Zeigt deine Countdowns an",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "remove",
+				Description: "Löscht einen Countdown",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "ID des Countdowns (siehe /countdown list)",
+						Required:    true,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /countdown: %v", err)
+	}
+
+	_, err = dg.ApplicationCommandCreate(dg.State.User.ID, "", &discordgo.ApplicationCommand{
+		Name:        "stats",
+		Description: "Statistiken zur Vorlesungsanwesenheit",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "vorlesungen",
+				Description: "Zeigt deine Anwesenheit, Streaks und Stunden pro Modul",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "export",
+				Description: "Exportiert alle Anwesenheiten als CSV (nur für Admins)",
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Fehler beim Registrieren von /stats: %v", err)
+	}
+
 	log.Println("✅ Alle Slash-Befehle erfolgreich registriert!")
 
 	// Timer starten
 	log.Println("Starte Timer...")
-	timer.StartLectureTimer(dg)
-	timer.StartProgressUpdater(dg)
+	timer.StartLectureTimer(dg, db)
+	timer.StartProgressUpdater(dg, db)
+	timer.StartCountdownNotifier(dg, db)
 
 	log.Println("🎉 Bot läuft erfolgreich! Drücke STRG+C zum Beenden.")
 